@@ -0,0 +1,14 @@
+package configs
+
+import "time"
+
+type KafkaConfig struct {
+	Brokers         string
+	Topic           string
+	GroupID         string
+	BatchSize       int
+	BatchWindow     time.Duration
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
@@ -0,0 +1,125 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	configs "linkfast/analytics/config"
+	models "linkfast/analytics/model"
+	"linkfast/analytics/services"
+	"log"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const retryDelay = 5 * time.Second
+
+func ClickConsumer(cfg configs.KafkaConfig, service services.StatsService) {
+	brokers, topic := cfg.Brokers, cfg.Topic
+
+	for {
+		log.Printf("Trying to connect to Kafka at %s...", brokers)
+
+		consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+			"bootstrap.servers":  brokers,
+			"group.id":           cfg.GroupID,
+			"auto.offset.reset":  "earliest",
+			"enable.auto.commit": false,
+			"socket.timeout.ms":  3000,
+		})
+
+		if err != nil {
+			log.Printf("Failed to create the Kafka consumer: %v. Retrying in %s...", err, retryDelay)
+			time.Sleep(retryDelay)
+			continue
+		}
+
+		if err := consumer.Subscribe(topic, nil); err != nil {
+			consumer.Close()
+			log.Printf("Failed to subscribe to topic %s: %v. Retrying in %s...", topic, err, retryDelay)
+			time.Sleep(retryDelay)
+			continue
+		}
+
+		log.Printf("Subscribed to topic '%s'. Consuming click events.", topic)
+
+		consumeLoop(consumer, cfg, service)
+
+		log.Printf("Consume loop finished. Reconnecting to Kafka in %s...", retryDelay)
+		consumer.Close()
+		time.Sleep(retryDelay)
+	}
+}
+
+func consumeLoop(consumer *kafka.Consumer, cfg configs.KafkaConfig, service services.StatsService) {
+	batch := make([]*kafka.Message, 0, cfg.BatchSize)
+	var batchStarted time.Time
+
+	for {
+		timeout := time.Second
+		if len(batch) > 0 {
+			timeout = max(time.Until(batchStarted.Add(cfg.BatchWindow)), time.Millisecond)
+		}
+
+		msg, err := consumer.ReadMessage(timeout)
+
+		if err == nil {
+			if len(batch) == 0 {
+				batchStarted = time.Now()
+			}
+			batch = append(batch, msg)
+
+		} else if kafkaErr, ok := err.(kafka.Error); ok {
+			if kafkaErr.Code() == kafka.ErrAllBrokersDown || kafkaErr.IsFatal() {
+				log.Printf("Fatal consumer error, reconnecting: %v", err)
+				return
+			} else if kafkaErr.Code() != kafka.ErrTimedOut {
+				log.Printf("Consumer error: %v", err)
+			}
+		} else {
+			log.Printf("Unexpected error while reading: %v", err)
+		}
+
+		if len(batch) == 0 || (len(batch) < cfg.BatchSize && time.Since(batchStarted) < cfg.BatchWindow) {
+			continue
+		}
+
+		if err := processBatch(batch, cfg, service); err != nil {
+			log.Printf("ERROR: Batch of %d click events could not be aggregated, re-consuming from last commit: %v", len(batch), err)
+			return
+		}
+
+		if _, err := consumer.Commit(); err != nil {
+			log.Printf("Failed to commit the batch offsets: %v", err)
+		}
+
+		batch = batch[:0]
+	}
+}
+
+// processBatch skips malformed events (there is nothing to replay them into) and retries the
+// aggregation write. Delivery is at-least-once, so a crash between the write and the commit
+// may count a batch twice.
+func processBatch(batch []*kafka.Message, cfg configs.KafkaConfig, service services.StatsService) error {
+	clicks := make([]models.ClickEvent, 0, len(batch))
+
+	for _, msg := range batch {
+		var event models.ClickEvent
+
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			log.Printf("WARN: Skipping unparseable click event %v: %v", msg.TopicPartition, err)
+			continue
+		}
+
+		if err := services.ValidateEvent(event); err != nil {
+			log.Printf("WARN: Skipping click event %v: %v", msg.TopicPartition, err)
+			continue
+		}
+
+		clicks = append(clicks, event)
+	}
+
+	return retryWithBackoff(cfg, func() error {
+		return service.ApplyBatch(context.Background(), clicks)
+	})
+}
@@ -0,0 +1,14 @@
+package consumer
+
+import (
+	configs "linkfast/analytics/config"
+	"linkfast/shared/retry"
+)
+
+func retryWithBackoff(cfg configs.KafkaConfig, fn func() error) error {
+	return retry.Do(retry.Policy{
+		MaxRetries: cfg.MaxRetries,
+		Backoff:    cfg.RetryBackoff,
+		MaxBackoff: cfg.MaxRetryBackoff,
+	}, fn)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	configs "linkfast/analytics/config"
+	"linkfast/analytics/consumer"
+	"linkfast/analytics/repositories"
+	"linkfast/analytics/services"
+	"linkfast/analytics/utils/envs"
+	"log"
+	"time"
+)
+
+func main() {
+	log.Println("Starting analytics service....")
+
+	log.Println("Waiting the anothers container startup")
+	time.Sleep(15 * time.Second)
+
+	kafkaBrokers := envs.GetEnvWithFallback("KAFKA_BROKERS", "")
+	kafkaTopic := envs.GetEnvWithFallback("CLICK_EVENTS_TOPIC", "")
+	mongoURI := envs.GetEnvWithFallback("MONGO_URI", "")
+	mongoDBName := envs.GetEnvWithFallback("MONGO_DB_NAME", "")
+	kafkaGroupID := envs.GetEnvWithFallback("KAFKA_GROUP_ID", "link_fast_analytics")
+	batchSize := envs.GetEnvAsIntWithFallback("ANALYTICS_BATCH_SIZE", 1000)
+	batchWindowMs := envs.GetEnvAsIntWithFallback("ANALYTICS_BATCH_WINDOW_MS", 1000)
+	maxRetries := envs.GetEnvAsIntWithFallback("ANALYTICS_MAX_RETRIES", 5)
+	retryBackoffMs := envs.GetEnvAsIntWithFallback("ANALYTICS_RETRY_BACKOFF_MS", 500)
+	maxRetryBackoffMs := envs.GetEnvAsIntWithFallback("ANALYTICS_RETRY_MAX_BACKOFF_MS", 10000)
+
+	required := map[string]string{
+		"KAFKA_BROKERS":      kafkaBrokers,
+		"CLICK_EVENTS_TOPIC": kafkaTopic,
+		"MONGO_URI":          mongoURI,
+		"MONGO_DB_NAME":      mongoDBName,
+	}
+
+	for key, value := range required {
+		if value == "" {
+			log.Fatalf("Environment variable %s not defined!", key)
+		}
+	}
+
+	mongoClient, err := configs.InitMongoDBConnection(configs.MongoConfig{
+		URI:    mongoURI,
+		DBName: mongoDBName,
+	})
+	if err != nil {
+		log.Fatalf("Critical failure connecting to MongoDB: %v", err)
+	}
+
+	defer func() {
+		if err = mongoClient.Disconnect(context.Background()); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	statsRepo := repositories.NewStatsRepository(mongoClient.Database(mongoDBName))
+
+	indexCtx, cancelIndex := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := statsRepo.EnsureIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to ensure indexes on link_stats collection: %v", err)
+	}
+	cancelIndex()
+
+	statsService := services.NewStatsService(statsRepo)
+
+	log.Println("Reading click events!")
+	consumer.ClickConsumer(configs.KafkaConfig{
+		Brokers:         kafkaBrokers,
+		Topic:           kafkaTopic,
+		GroupID:         kafkaGroupID,
+		BatchSize:       max(batchSize, 1),
+		BatchWindow:     time.Duration(batchWindowMs) * time.Millisecond,
+		MaxRetries:      maxRetries,
+		RetryBackoff:    time.Duration(retryBackoffMs) * time.Millisecond,
+		MaxRetryBackoff: time.Duration(maxRetryBackoffMs) * time.Millisecond,
+	}, statsService)
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type ClickEvent struct {
+	EventID   string    `json:"event_id"`
+	ShortCode string    `json:"short_code"`
+	LinkID    int64     `json:"link_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Referrer  string    `json:"referrer"`
+	UserAgent string    `json:"user_agent"`
+	IPHash    string    `json:"ip_hash"`
+	IPPrefix  string    `json:"ip_prefix"`
+	Country   string    `json:"country"`
+	Variant   string    `json:"variant"`
+	TraceID   string    `json:"trace_id"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	GranularityHour = "hour"
+	GranularityDay  = "day"
+)
+
+type LinkStats struct {
+	LinkID      int64            `bson:"link_id"`
+	ShortCode   string           `bson:"short_code"`
+	Granularity string           `bson:"granularity"`
+	Bucket      time.Time        `bson:"bucket"`
+	Clicks      int64            `bson:"clicks"`
+	Referrers   map[string]int64 `bson:"referrers"`
+	Countries   map[string]int64 `bson:"countries"`
+	Devices     map[string]int64 `bson:"devices"`
+	Browsers    map[string]int64 `bson:"browsers"`
+	Variants    map[string]int64 `bson:"variants"`
+	UpdatedAt   time.Time        `bson:"updated_at"`
+}
+
+var keyEscaper = strings.NewReplacer("%", "%25", ".", "%2E", "$", "%24")
+
+// EscapeKey makes a breakdown value safe to use as a Mongo field name (no "." or "$").
+func EscapeKey(key string) string {
+	if key == "" {
+		return "unknown"
+	}
+	return keyEscaper.Replace(key)
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	models "linkfast/analytics/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BucketKey struct {
+	LinkID      int64
+	Granularity string
+	Bucket      time.Time
+}
+
+type BucketIncrement struct {
+	BucketKey
+	ShortCode string
+	Clicks    int64
+	Referrers map[string]int64
+	Countries map[string]int64
+	Devices   map[string]int64
+	Browsers  map[string]int64
+	Variants  map[string]int64
+}
+
+type StatsRepository interface {
+	IncrementBuckets(ctx context.Context, increments []BucketIncrement) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type statsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewStatsRepository(db *mongo.Database) StatsRepository {
+	return &statsRepository{
+		collection: db.Collection("link_stats"),
+	}
+}
+
+func (s *statsRepository) IncrementBuckets(ctx context.Context, increments []BucketIncrement) error {
+	if len(increments) == 0 {
+		return nil
+	}
+
+	operations := make([]mongo.WriteModel, 0, len(increments))
+	for _, increment := range increments {
+		inc := bson.M{"clicks": increment.Clicks}
+		addBreakdown(inc, "referrers", increment.Referrers)
+		addBreakdown(inc, "countries", increment.Countries)
+		addBreakdown(inc, "devices", increment.Devices)
+		addBreakdown(inc, "browsers", increment.Browsers)
+		addBreakdown(inc, "variants", increment.Variants)
+
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"link_id":     increment.LinkID,
+				"granularity": increment.Granularity,
+				"bucket":      increment.Bucket,
+			}).
+			SetUpdate(bson.M{
+				"$inc": inc,
+				"$set": bson.M{"short_code": increment.ShortCode, "updated_at": time.Now()},
+			}).
+			SetUpsert(true))
+	}
+
+	_, err := s.collection.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (s *statsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "link_id", Value: 1},
+			{Key: "granularity", Value: 1},
+			{Key: "bucket", Value: 1},
+		},
+		Options: options.Index().SetName("link_granularity_bucket_unique").SetUnique(true),
+	})
+	return err
+}
+
+func addBreakdown(inc bson.M, field string, counts map[string]int64) {
+	for key, count := range counts {
+		inc[field+"."+models.EscapeKey(key)] = count
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	models "linkfast/analytics/model"
+	"linkfast/analytics/repositories"
+	"linkfast/analytics/utils/consts"
+	"linkfast/shared/useragent"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type StatsService interface {
+	ApplyBatch(ctx context.Context, events []models.ClickEvent) error
+}
+
+type statsService struct {
+	repo repositories.StatsRepository
+}
+
+func NewStatsService(repo repositories.StatsRepository) StatsService {
+	return &statsService{
+		repo: repo,
+	}
+}
+
+func (s *statsService) ApplyBatch(ctx context.Context, events []models.ClickEvent) error {
+	increments := Aggregate(events)
+	if len(increments) == 0 {
+		return nil
+	}
+
+	if err := s.repo.IncrementBuckets(ctx, increments); err != nil {
+		return err
+	}
+
+	log.Printf("SUCCESS: %d click events aggregated into %d buckets", len(events), len(increments))
+	return nil
+}
+
+// ValidateEvent rejects events that cannot be attributed to a link or a point in time.
+func ValidateEvent(event models.ClickEvent) error {
+	if event.LinkID <= 0 {
+		return fmt.Errorf("%w: link_id is required", consts.ErrInvalidEvent)
+	}
+
+	if event.Timestamp.IsZero() {
+		return fmt.Errorf("%w: timestamp is required", consts.ErrInvalidEvent)
+	}
+
+	return nil
+}
+
+// Aggregate folds the events into one increment per link, granularity and bucket, so a
+// batch results in a single $inc per bucket instead of one per click.
+func Aggregate(events []models.ClickEvent) []repositories.BucketIncrement {
+	increments := map[repositories.BucketKey]*repositories.BucketIncrement{}
+	order := []repositories.BucketKey{}
+
+	for _, event := range events {
+		client := useragent.Parse(event.UserAgent)
+		timestamp := event.Timestamp.UTC()
+
+		buckets := []repositories.BucketKey{
+			{LinkID: event.LinkID, Granularity: models.GranularityHour, Bucket: timestamp.Truncate(time.Hour)},
+			{LinkID: event.LinkID, Granularity: models.GranularityDay, Bucket: time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, time.UTC)},
+		}
+
+		for _, key := range buckets {
+			increment, ok := increments[key]
+			if !ok {
+				increment = &repositories.BucketIncrement{
+					BucketKey: key,
+					Referrers: map[string]int64{},
+					Countries: map[string]int64{},
+					Devices:   map[string]int64{},
+					Browsers:  map[string]int64{},
+					Variants:  map[string]int64{},
+				}
+				increments[key] = increment
+				order = append(order, key)
+			}
+
+			increment.ShortCode = event.ShortCode
+			increment.Clicks++
+			increment.Referrers[referrerHost(event.Referrer)]++
+			increment.Countries[country(event.Country)]++
+			increment.Devices[client.Device]++
+			increment.Browsers[client.Browser]++
+			if event.Variant != "" {
+				increment.Variants[event.Variant]++
+			}
+		}
+	}
+
+	result := make([]repositories.BucketIncrement, 0, len(order))
+	for _, key := range order {
+		result = append(result, *increments[key])
+	}
+
+	return result
+}
+
+func referrerHost(referrer string) string {
+	if referrer == "" {
+		return "direct"
+	}
+
+	parsed, err := url.Parse(referrer)
+	if err != nil || parsed.Hostname() == "" {
+		return "other"
+	}
+
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
+
+func country(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 {
+		return "unknown"
+	}
+	return code
+}
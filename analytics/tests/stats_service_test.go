@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	models "linkfast/analytics/model"
+	"linkfast/analytics/repositories"
+	"linkfast/analytics/services"
+	"linkfast/analytics/utils/consts"
+)
+
+const chromeDesktop = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+const safariIPhone = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1"
+
+type fakeStatsRepository struct {
+	increments []repositories.BucketIncrement
+	err        error
+}
+
+func (f *fakeStatsRepository) IncrementBuckets(ctx context.Context, increments []repositories.BucketIncrement) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.increments = append(f.increments, increments...)
+	return nil
+}
+
+func (f *fakeStatsRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+func click(linkID int64, timestamp string, referrer, ua, country string) models.ClickEvent {
+	parsed, _ := time.Parse(time.RFC3339, timestamp)
+	return models.ClickEvent{
+		LinkID:    linkID,
+		ShortCode: "abc1234",
+		Timestamp: parsed,
+		Referrer:  referrer,
+		UserAgent: ua,
+		Country:   country,
+	}
+}
+
+func TestAggregate_BucketsAndBreakdowns(t *testing.T) {
+	increments := services.Aggregate([]models.ClickEvent{
+		click(1, "2025-12-07T10:15:00Z", "https://www.google.com/search?q=x", chromeDesktop, "br"),
+		click(1, "2025-12-07T10:45:00Z", "", safariIPhone, "BR"),
+		click(1, "2025-12-07T11:05:00Z", "https://t.co/abc", chromeDesktop, ""),
+		click(2, "2025-12-07T10:20:00Z", "", chromeDesktop, "US"),
+	})
+
+	byKey := map[repositories.BucketKey]repositories.BucketIncrement{}
+	for _, increment := range increments {
+		byKey[increment.BucketKey] = increment
+	}
+
+	if len(byKey) != 5 {
+		t.Fatalf("Esperado 5 buckets (3 horários e 2 diários), obtidos %d", len(byKey))
+	}
+
+	tenOClock := byKey[repositories.BucketKey{LinkID: 1, Granularity: models.GranularityHour, Bucket: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)}]
+	if tenOClock.Clicks != 2 {
+		t.Errorf("Esperado 2 cliques no bucket das 10h, obtidos %d", tenOClock.Clicks)
+	}
+
+	if tenOClock.Referrers["google.com"] != 1 || tenOClock.Referrers["direct"] != 1 {
+		t.Errorf("Referrers inesperados: %v", tenOClock.Referrers)
+	}
+
+	if tenOClock.Countries["BR"] != 2 {
+		t.Errorf("Países inesperados: %v", tenOClock.Countries)
+	}
+
+	if tenOClock.Devices["desktop"] != 1 || tenOClock.Devices["mobile"] != 1 {
+		t.Errorf("Dispositivos inesperados: %v", tenOClock.Devices)
+	}
+
+	day := byKey[repositories.BucketKey{LinkID: 1, Granularity: models.GranularityDay, Bucket: time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)}]
+	if day.Clicks != 3 {
+		t.Errorf("Esperado 3 cliques no dia, obtidos %d", day.Clicks)
+	}
+
+	if day.Countries["unknown"] != 1 || day.Referrers["t.co"] != 1 || day.Browsers["Chrome"] != 2 {
+		t.Errorf("Breakdown diário inesperado: %+v", day)
+	}
+}
+
+func TestAggregate_VariantBreakdown(t *testing.T) {
+	withVariant := func(event models.ClickEvent, variant string) models.ClickEvent {
+		event.Variant = variant
+		return event
+	}
+
+	increments := services.Aggregate([]models.ClickEvent{
+		withVariant(click(1, "2025-12-07T10:15:00Z", "", chromeDesktop, "BR"), "a"),
+		withVariant(click(1, "2025-12-07T10:20:00Z", "", chromeDesktop, "BR"), "b"),
+		withVariant(click(1, "2025-12-07T10:25:00Z", "", safariIPhone, "BR"), "a"),
+		click(1, "2025-12-07T10:30:00Z", "", chromeDesktop, "BR"),
+	})
+
+	for _, increment := range increments {
+		if increment.Clicks != 4 {
+			t.Fatalf("Esperado 4 cliques no bucket %s, obtidos %d", increment.Granularity, increment.Clicks)
+		}
+
+		if len(increment.Variants) != 2 || increment.Variants["a"] != 2 || increment.Variants["b"] != 1 {
+			t.Errorf("Variantes inesperadas no bucket %s: %v", increment.Granularity, increment.Variants)
+		}
+	}
+}
+
+func TestStatsService_ApplyBatch_PropagatesError(t *testing.T) {
+	repo := &fakeStatsRepository{err: consts.ErrInternalDB}
+	service := services.NewStatsService(repo)
+
+	err := service.ApplyBatch(context.Background(), []models.ClickEvent{
+		click(1, "2025-12-07T10:15:00Z", "", chromeDesktop, "BR"),
+	})
+
+	if !errors.Is(err, consts.ErrInternalDB) {
+		t.Errorf("Esperado ErrInternalDB, obtido %v", err)
+	}
+}
+
+func TestValidateEvent(t *testing.T) {
+	if err := services.ValidateEvent(models.ClickEvent{Timestamp: time.Now()}); !errors.Is(err, consts.ErrInvalidEvent) {
+		t.Errorf("Evento sem link_id deveria ser inválido, obtido %v", err)
+	}
+
+	if err := services.ValidateEvent(models.ClickEvent{LinkID: 1}); !errors.Is(err, consts.ErrInvalidEvent) {
+		t.Errorf("Evento sem timestamp deveria ser inválido, obtido %v", err)
+	}
+
+	if err := services.ValidateEvent(click(1, "2025-12-07T10:15:00Z", "", "", "")); err != nil {
+		t.Errorf("Erro inesperado: %v", err)
+	}
+}
+
+func TestEscapeKey(t *testing.T) {
+	tests := map[string]string{
+		"google.com": "google%2Ecom",
+		"$where":     "%24where",
+		"50%":        "50%25",
+		"":           "unknown",
+	}
+
+	for input, expected := range tests {
+		if escaped := models.EscapeKey(input); escaped != expected {
+			t.Errorf("EscapeKey(%q): esperado %q, obtido %q", input, expected, escaped)
+		}
+	}
+}
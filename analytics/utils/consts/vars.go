@@ -0,0 +1,8 @@
+package consts
+
+import "errors"
+
+var (
+	ErrInternalDB   = errors.New("internal database error.")
+	ErrInvalidEvent = errors.New("invalid click event")
+)
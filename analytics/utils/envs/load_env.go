@@ -0,0 +1,29 @@
+package envs
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+func GetEnvWithFallback(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func GetEnvAsIntWithFallback(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Environment variable %s is not a valid integer (%s). Using fallback %d", key, value, fallback)
+		return fallback
+	}
+
+	return parsed
+}
@@ -0,0 +1,13 @@
+package configs
+
+import "time"
+
+type CacheConfig struct {
+	Capacity          int
+	TTL               time.Duration
+	NegativeTTL       time.Duration
+	Brokers           string
+	InvalidationTopic string
+	// Unique per instance and stable across its restarts; defaults to one named after the host.
+	InvalidationGroupID string
+}
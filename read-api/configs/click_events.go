@@ -0,0 +1,9 @@
+package configs
+
+type ClickEventsConfig struct {
+	Brokers       string
+	Topic         string
+	BufferSize    int
+	IPHashSalt    string
+	CountryHeader string
+}
@@ -0,0 +1,6 @@
+package configs
+
+type GeoConfig struct {
+	DatabasePath   string
+	TrustedProxies []string
+}
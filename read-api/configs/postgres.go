@@ -0,0 +1,51 @@
+package configs
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FallbackConfig struct {
+	PostgresURL  string
+	Window       time.Duration
+	QueryTimeout time.Duration
+	MaxConns     int32
+}
+
+// AuthConfig verifies the credentials issued by the write API: JWTSecret must match its
+// JWT_SECRET, and API keys are looked up in its api_keys table through PostgresURL. Either
+// kind of credential is refused when its setting is empty.
+type AuthConfig struct {
+	JWTSecret    []byte
+	PostgresURL  string
+	QueryTimeout time.Duration
+	MaxConns     int32
+}
+
+// ConnectFallbackPostgres opens a small pool of read-only sessions against the write model.
+func ConnectFallbackPostgres(cfg FallbackConfig) (*pgxpool.Pool, error) {
+	return connectReadOnly(cfg.PostgresURL, cfg.MaxConns, "read_api_fallback")
+}
+
+// ConnectAuthPostgres opens the read-only pool used to check API keys.
+func ConnectAuthPostgres(cfg AuthConfig) (*pgxpool.Pool, error) {
+	return connectReadOnly(cfg.PostgresURL, cfg.MaxConns, "read_api_auth")
+}
+
+func connectReadOnly(url string, maxConns int32, applicationName string) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+
+	poolCfg.MaxConns = max(maxConns, 1)
+	poolCfg.ConnConfig.RuntimeParams["default_transaction_read_only"] = "on"
+	poolCfg.ConnConfig.RuntimeParams["application_name"] = applicationName
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return pgxpool.NewWithConfig(ctx, poolCfg)
+}
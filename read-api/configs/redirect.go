@@ -0,0 +1,10 @@
+package configs
+
+import "time"
+
+type RedirectConfig struct {
+	ExpiredURL          string
+	PendingURL          string
+	PermanentMaxAge     time.Duration
+	VariantCookieMaxAge time.Duration
+}
@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+type LinkListQueryDto struct {
+	Cursor      int64
+	Limit       int
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Expired     *bool
+	Domain      string
+	Search      string
+	OwnerID     int64
+}
+
+// LinkPageDto carries the cursor as a string because snowflake ids do not fit in a JSON number
+// for most clients. It is empty on the last page.
+type LinkPageDto struct {
+	Links      []LinkDto `json:"links"`
+	NextCursor string    `json:"next_cursor"`
+}
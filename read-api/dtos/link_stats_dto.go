@@ -0,0 +1,35 @@
+package dtos
+
+import "time"
+
+type StatsQueryDto struct {
+	Granularity string
+	From        time.Time
+	To          time.Time
+	// OwnerID only allows the stats of a link owned by that user; 0 allows any link.
+	OwnerID int64
+}
+
+type StatsBreakdownDto struct {
+	Referrers map[string]int64 `json:"referrers"`
+	Countries map[string]int64 `json:"countries"`
+	Devices   map[string]int64 `json:"devices"`
+	Browsers  map[string]int64 `json:"browsers"`
+	Variants  map[string]int64 `json:"variants"`
+}
+
+type StatsBucketDto struct {
+	Bucket time.Time `json:"bucket"`
+	Clicks int64     `json:"clicks"`
+	StatsBreakdownDto
+}
+
+type LinkStatsDto struct {
+	LinkID      int64             `json:"link_id"`
+	Granularity string            `json:"granularity"`
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	TotalClicks int64             `json:"total_clicks"`
+	Totals      StatsBreakdownDto `json:"totals"`
+	Series      []StatsBucketDto  `json:"series"`
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ClickEvent struct {
+	EventID   string    `json:"event_id"`
+	ShortCode string    `json:"short_code"`
+	LinkID    int64     `json:"link_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Referrer  string    `json:"referrer"`
+	UserAgent string    `json:"user_agent"`
+	IPHash    string    `json:"ip_hash"`
+	IPPrefix  string    `json:"ip_prefix"`
+	Country   string    `json:"country"`
+	Variant   string    `json:"variant,omitempty"`
+	TraceID   string    `json:"trace_id"`
+}
+
+type ClickInput struct {
+	ShortCode string
+	LinkID    int64
+	Referrer  string
+	UserAgent string
+	IP        string
+	Country   string
+	Variant   string
+	TraceID   string
+}
+
+// NewClickEvent builds the event without keeping the raw client IP: only a salted hash
+// (to count unique visitors) and the network prefix (/24 or /48) are published.
+func NewClickEvent(input ClickInput, ipHashSalt string) ClickEvent {
+	return ClickEvent{
+		EventID:   uuid.NewString(),
+		ShortCode: input.ShortCode,
+		LinkID:    input.LinkID,
+		Timestamp: time.Now().UTC(),
+		Referrer:  input.Referrer,
+		UserAgent: input.UserAgent,
+		IPHash:    hashIP(input.IP, ipHashSalt),
+		IPPrefix:  ipPrefix(input.IP),
+		Country:   input.Country,
+		Variant:   input.Variant,
+		TraceID:   input.TraceID,
+	}
+}
+
+func hashIP(ip, salt string) string {
+	if ip == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(salt + ip))
+	return hex.EncodeToString(sum[:])
+}
+
+func ipPrefix(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+}
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"encoding/json"
+	"linkfast/read-api/configs"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+type ClickPublisher interface {
+	// Publish enqueues the event without blocking and reports false when it was dropped.
+	Publish(event ClickEvent) bool
+	Dropped() int64
+	Close()
+}
+
+type kafkaClickPublisher struct {
+	producer *kafka.Producer
+	topic    string
+	queue    chan ClickEvent
+	dropped  atomic.Int64
+	produced sync.WaitGroup
+	reported sync.WaitGroup
+}
+
+func NewClickPublisher(cfg configs.ClickEventsConfig) (ClickPublisher, error) {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": cfg.Brokers,
+		"acks":              1,
+		"linger.ms":         50,
+		"compression.type":  "snappy",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 10000
+	}
+
+	publisher := &kafkaClickPublisher{
+		producer: producer,
+		topic:    cfg.Topic,
+		queue:    make(chan ClickEvent, bufferSize),
+	}
+
+	publisher.produced.Add(1)
+	publisher.reported.Add(1)
+	go publisher.produceLoop()
+	go publisher.reportLoop()
+
+	return publisher, nil
+}
+
+func (p *kafkaClickPublisher) Publish(event ClickEvent) bool {
+	select {
+	case p.queue <- event:
+		return true
+	default:
+		p.drop("buffer full")
+		return false
+	}
+}
+
+func (p *kafkaClickPublisher) Dropped() int64 {
+	return p.dropped.Load()
+}
+
+// Close must only be called once the HTTP server has stopped accepting requests.
+func (p *kafkaClickPublisher) Close() {
+	close(p.queue)
+	p.produced.Wait()
+	p.producer.Flush(5000)
+	p.producer.Close()
+	p.reported.Wait()
+}
+
+func (p *kafkaClickPublisher) produceLoop() {
+	defer p.produced.Done()
+
+	for event := range p.queue {
+		value, err := json.Marshal(event)
+		if err != nil {
+			p.drop(err.Error())
+			continue
+		}
+
+		err = p.producer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &p.topic, Partition: kafka.PartitionAny},
+			Key:            []byte(event.ShortCode),
+			Value:          value,
+		}, nil)
+		if err != nil {
+			p.drop(err.Error())
+		}
+	}
+}
+
+// reportLoop drains the producer's delivery reports so failed deliveries are counted.
+func (p *kafkaClickPublisher) reportLoop() {
+	defer p.reported.Done()
+
+	for e := range p.producer.Events() {
+		if msg, ok := e.(*kafka.Message); ok && msg.TopicPartition.Error != nil {
+			p.drop(msg.TopicPartition.Error.Error())
+		}
+	}
+}
+
+func (p *kafkaClickPublisher) drop(reason string) {
+	dropped := p.dropped.Add(1)
+	if dropped == 1 || dropped%1000 == 0 {
+		log.Printf("WARN: Click event dropped (%s). Total dropped: %d", reason, dropped)
+	}
+}
+
+type noopClickPublisher struct{}
+
+// NewNoopClickPublisher is used when click tracking is disabled.
+func NewNoopClickPublisher() ClickPublisher {
+	return noopClickPublisher{}
+}
+
+func (noopClickPublisher) Publish(event ClickEvent) bool { return false }
+
+func (noopClickPublisher) Dropped() int64 { return 0 }
+
+func (noopClickPublisher) Close() {}
@@ -0,0 +1,130 @@
+package geo
+
+import (
+	"fmt"
+	"linkfast/read-api/configs"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// Locator resolves the visitor's country from a local MaxMind-format database. Without a
+// database every lookup returns "", so geo-targeted links fall back to their long_url.
+type Locator struct {
+	reader  *geoip2.Reader
+	trusted []netip.Prefix
+}
+
+func NewLocator(cfg configs.GeoConfig) (*Locator, error) {
+	trusted, err := parseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	locator := &Locator{trusted: trusted}
+	if cfg.DatabasePath == "" {
+		return locator, nil
+	}
+
+	reader, err := geoip2.Open(cfg.DatabasePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the GeoIP database %s: %w", cfg.DatabasePath, err)
+	}
+	locator.reader = reader
+
+	return locator, nil
+}
+
+func parseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+func (l *Locator) isTrusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range l.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP honours X-Forwarded-For only when the direct peer is a trusted proxy, and walks
+// it from the right so an address the client wrote itself is never picked over the last
+// untrusted hop.
+func (l *Locator) ClientIP(remoteIP, forwardedFor string) string {
+	if forwardedFor == "" || !l.isTrusted(remoteIP) {
+		return remoteIP
+	}
+
+	client := remoteIP
+	hops := strings.Split(forwardedFor, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+
+		client = hop
+		if !l.isTrusted(hop) {
+			break
+		}
+	}
+
+	return client
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of ip, or "" when it cannot be resolved.
+func (l *Locator) Country(ip string) string {
+	if l.reader == nil {
+		return ""
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+
+	record, err := l.reader.Country(parsed)
+	if err != nil {
+		return ""
+	}
+
+	return record.Country.IsoCode
+}
+
+func (l *Locator) Close() {
+	if l.reader != nil {
+		l.reader.Close()
+	}
+}
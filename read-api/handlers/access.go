@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"linkfast/read-api/middlewares"
+	"linkfast/read-api/models"
+	"linkfast/read-api/utils/res"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// canAccess lets users reach only the links they own, unless they are admins. API keys are
+// operator credentials and reach every link, as do routes outside the authentication middleware.
+func canAccess(c *fiber.Ctx, link models.Link) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil || user.IsAdmin() {
+		return true
+	}
+	return link.OwnerID != nil && *link.OwnerID == user.UserID
+}
+
+func forbidden(c *fiber.Ctx, traceID string) error {
+	return c.Status(fiber.StatusForbidden).JSON(
+		res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      fiber.StatusForbidden,
+			Status:    false,
+			Message:   "You do not have access to this link",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
@@ -2,27 +2,48 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"linkfast/read-api/configs"
 	"linkfast/read-api/dtos"
+	"linkfast/read-api/events"
+	"linkfast/read-api/geo"
+	"linkfast/read-api/models"
 	"linkfast/read-api/services"
+	"linkfast/read-api/throttle"
 	"linkfast/read-api/utils/consts"
 	"linkfast/read-api/utils/res"
+	"linkfast/shared/useragent"
+	"math"
+	"math/rand/v2"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/jinzhu/copier"
+	"golang.org/x/crypto/bcrypt"
 )
 
+const variantCookiePrefix = "lf_v_"
+
 type LinkHandler interface {
 	GetByID(c *fiber.Ctx) error
 	GetByShotCode(c *fiber.Ctx) error
+	Unlock(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
 }
 
 type linkHandler struct {
-	service services.LinkService
+	service  services.LinkService
+	redirect configs.RedirectConfig
+	clicks   events.ClickPublisher
+	clickCfg configs.ClickEventsConfig
+	unlocks  *throttle.Limiter
+	locator  *geo.Locator
 }
 
-func NewLinkHandler(service services.LinkService) LinkHandler {
-	return &linkHandler{service: service}
+func NewLinkHandler(service services.LinkService, redirect configs.RedirectConfig, clicks events.ClickPublisher, clickCfg configs.ClickEventsConfig, unlocks *throttle.Limiter, locator *geo.Locator) LinkHandler {
+	return &linkHandler{service: service, redirect: redirect, clicks: clicks, clickCfg: clickCfg, unlocks: unlocks, locator: locator}
 }
 
 func (h *linkHandler) GetByID(c *fiber.Ctx) error {
@@ -95,6 +116,10 @@ func (h *linkHandler) GetByID(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(res)
 	}
 
+	if !canAccess(c, link) {
+		return forbidden(c, traceID)
+	}
+
 	var linkDTO dtos.LinkDto
 	if err := copier.Copy(&linkDTO, &link); err != nil {
 		res := res.ResponseHttp[string]{
@@ -148,37 +173,292 @@ func (h *linkHandler) GetByShotCode(c *fiber.Ctx) error {
 	}
 
 	link, err := h.service.GetByCode(c.Context(), shortCode)
+	if err != nil {
+		return h.lookupError(c, link, err, traceID)
+	}
+
+	if link.PasswordProtected() {
+		c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+		return renderUnlockPage(c, fiber.StatusOK, "")
+	}
+
+	if err := h.service.ConsumeClick(c.Context(), link); err != nil {
+		return h.lookupError(c, link, err, traceID)
+	}
+
+	destination, variant := h.destination(c, link)
+	h.trackClick(c, link.ID, shortCode, traceID, variant)
 
+	c.Set(fiber.HeaderCacheControl, h.cacheControl(link, time.Now()))
+	return c.Redirect(destination, link.RedirectStatus())
+}
+
+// Unlock verifies the password posted by the unlock form. Failures are throttled per short
+// code, so guessing is slowed down no matter how many addresses the attacker uses.
+func (h *linkHandler) Unlock(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	shortCode := c.Params("code")
+	if shortCode == "" {
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      fiber.StatusBadRequest,
+			Status:    false,
+			Message:   "Code is required",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(res)
+	}
+
+	link, err := h.service.GetByCode(c.Context(), shortCode)
 	if err != nil {
-		if errors.Is(err, consts.ErrRecordNotFound) {
-			res := res.ResponseHttp[string]{
-				Timestamp: time.Now(),
-				Payload:   "",
-				Code:      fiber.StatusNotFound,
-				Status:    false,
-				Message:   "Link not found",
-				Version:   1,
-				TraceID:   traceID,
-				Path:      "",
-			}
+		return h.lookupError(c, link, err, traceID)
+	}
 
-			return c.Status(fiber.StatusNotFound).JSON(res)
+	c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+
+	if link.PasswordProtected() {
+		if allowed, retryAfter := h.unlocks.Reserve(utils.CopyString(shortCode)); !allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			return renderUnlockPage(c, fiber.StatusTooManyRequests, "Too many attempts. Try again later.")
 		}
 
+		if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(c.FormValue("password"))) != nil {
+			return renderUnlockPage(c, fiber.StatusUnauthorized, "Incorrect password.")
+		}
+
+		h.unlocks.Reset(shortCode)
+	}
+
+	if err := h.service.ConsumeClick(c.Context(), link); err != nil {
+		return h.lookupError(c, link, err, traceID)
+	}
+
+	destination, variant := h.destination(c, link)
+	h.trackClick(c, link.ID, shortCode, traceID, variant)
+
+	// 303 makes the browser follow with a GET, so the password is never re-posted to the destination.
+	return c.Redirect(destination, fiber.StatusSeeOther)
+}
+
+func (h *linkHandler) lookupError(c *fiber.Ctx, link models.Link, err error, traceID string) error {
+	if errors.Is(err, consts.ErrRecordNotFound) {
 		res := res.ResponseHttp[string]{
 			Timestamp: time.Now(),
-			Payload:   err.Error(),
-			Code:      fiber.StatusInternalServerError,
+			Payload:   "",
+			Code:      fiber.StatusNotFound,
 			Status:    false,
-			Message:   "Error internal in server! Try again later",
+			Message:   "Link not found",
 			Version:   1,
 			TraceID:   traceID,
 			Path:      "",
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(res)
+		return c.Status(fiber.StatusNotFound).JSON(res)
 	}
 
-	c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
-	return c.Redirect(link.LONG_URL, fiber.StatusTemporaryRedirect)
+	if errors.Is(err, consts.ErrLinkExpired) {
+		if h.redirect.ExpiredURL != "" {
+			c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+			return c.Redirect(h.redirect.ExpiredURL, fiber.StatusFound)
+		}
+
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      fiber.StatusGone,
+			Status:    false,
+			Message:   "Link expired",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusGone).JSON(res)
+	}
+
+	// Pending links answer 403 until launch; Retry-After tells clients when to come back.
+	if errors.Is(err, consts.ErrLinkPending) {
+		c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+		if h.redirect.PendingURL != "" {
+			return c.Redirect(h.redirect.PendingURL, fiber.StatusFound)
+		}
+
+		retryAfter := int64(math.Ceil(time.Until(*link.ActivatesAt).Seconds()))
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(max(retryAfter, 1), 10))
+
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   link.ActivatesAt.UTC().Format(time.RFC3339),
+			Code:      fiber.StatusForbidden,
+			Status:    false,
+			Message:   "Link not available yet",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(res)
+	}
+
+	// A capped link still in the projection lag cannot count its clicks yet; it is refused
+	// briefly rather than redirected past its cap.
+	if errors.Is(err, consts.ErrNotProjected) {
+		c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+		c.Set(fiber.HeaderRetryAfter, "1")
+
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      fiber.StatusServiceUnavailable,
+			Status:    false,
+			Message:   "Link not available yet, try again shortly",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusServiceUnavailable).JSON(res)
+	}
+
+	if errors.Is(err, consts.ErrClickLimit) {
+		c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      fiber.StatusGone,
+			Status:    false,
+			Message:   "Link click limit reached",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusGone).JSON(res)
+	}
+
+	res := res.ResponseHttp[string]{
+		Timestamp: time.Now(),
+		Payload:   err.Error(),
+		Code:      fiber.StatusInternalServerError,
+		Status:    false,
+		Message:   "Error internal in server! Try again later",
+		Version:   1,
+		TraceID:   traceID,
+		Path:      "",
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(res)
+}
+
+// cacheControl lets browsers and CDNs keep permanent redirects, but never beyond the link's
+// expiration; temporary redirects and capped links always come back so every click is counted.
+// Targeted redirects differ per visitor, so shared caches must not keep them.
+func (h *linkHandler) cacheControl(link models.Link, now time.Time) string {
+	if !link.IsPermanentRedirect() || link.MaxClicks != nil || h.redirect.PermanentMaxAge <= 0 {
+		return "no-store, no-cache, must-revalidate, max-age=0"
+	}
+
+	maxAge := h.redirect.PermanentMaxAge
+	if link.ExpiresAt != nil {
+		maxAge = min(maxAge, link.ExpiresAt.Sub(now))
+	}
+
+	if maxAge < time.Second {
+		return "no-store, no-cache, must-revalidate, max-age=0"
+	}
+
+	if link.IsTargeted() {
+		return fmt.Sprintf("private, max-age=%d", int64(maxAge.Seconds()))
+	}
+
+	return fmt.Sprintf("public, max-age=%d", int64(maxAge.Seconds()))
+}
+
+// destination applies the device rules first, then the geo rules, then the weighted variants,
+// and falls back to long_url. It also returns the variant name when one was picked.
+// Each lookup only runs when the link has rules of that kind.
+func (h *linkHandler) destination(c *fiber.Ctx, link models.Link) (string, string) {
+	if link.IsDeviceTargeted() {
+		client := useragent.Parse(c.Get(fiber.HeaderUserAgent))
+		if target, ok := link.DeviceTarget(client.OS, client.Device); ok {
+			return target, ""
+		}
+	}
+
+	if link.IsGeoTargeted() {
+		if target, ok := link.GeoTarget(h.locator.Country(h.clientIP(c))); ok {
+			return target, ""
+		}
+	}
+
+	if link.HasVariants() && link.TotalWeight() > 0 {
+		variant := h.pickVariant(c, link)
+		return variant.URL, variant.Name
+	}
+
+	return link.LONG_URL, ""
+}
+
+// pickVariant draws a variant by weight. Sticky links remember the draw in a cookie scoped to
+// the short code, so a returning visitor keeps seeing the same landing page.
+func (h *linkHandler) pickVariant(c *fiber.Ctx, link models.Link) models.LinkVariant {
+	cookieName := variantCookiePrefix + link.SHORT_CODE
+
+	if link.StickyVariants {
+		if variant, ok := link.Variant(c.Cookies(cookieName)); ok {
+			return variant
+		}
+	}
+
+	variant := link.PickVariant(rand.IntN(link.TotalWeight()))
+
+	if link.StickyVariants {
+		c.Cookie(&fiber.Cookie{
+			Name:     cookieName,
+			Value:    variant.Name,
+			Path:     c.Path(),
+			MaxAge:   int(h.redirect.VariantCookieMaxAge.Seconds()),
+			HTTPOnly: true,
+			Secure:   c.Protocol() == "https",
+			SameSite: fiber.CookieSameSiteLaxMode,
+		})
+	}
+
+	return variant
+}
+
+// clientIP is the visitor address, read from X-Forwarded-For only behind a trusted proxy, so
+// geo rules and click events agree on who clicked.
+func (h *linkHandler) clientIP(c *fiber.Ctx) string {
+	return h.locator.ClientIP(c.Context().RemoteIP().String(), c.Get(fiber.HeaderXForwardedFor))
+}
+
+// trackClick copies the request values (fasthttp reuses its buffers after the handler returns) and enqueues the event without blocking.
+func (h *linkHandler) trackClick(c *fiber.Ctx, linkID int64, shortCode, traceID, variant string) {
+	ip := h.clientIP(c)
+
+	// Without an edge header naming the country, fall back to the GeoIP database the geo rules use.
+	country := h.locator.Country(ip)
+	if h.clickCfg.CountryHeader != "" {
+		country = utils.CopyString(c.Get(h.clickCfg.CountryHeader))
+	}
+
+	h.clicks.Publish(events.NewClickEvent(events.ClickInput{
+		ShortCode: utils.CopyString(shortCode),
+		LinkID:    linkID,
+		Referrer:  utils.CopyString(c.Get(fiber.HeaderReferer)),
+		UserAgent: utils.CopyString(c.Get(fiber.HeaderUserAgent)),
+		IP:        utils.CopyString(ip),
+		Country:   country,
+		Variant:   variant,
+		TraceID:   utils.CopyString(traceID),
+	}, h.clickCfg.IPHashSalt))
 }
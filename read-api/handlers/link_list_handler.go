@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"linkfast/read-api/dtos"
+	"linkfast/read-api/middlewares"
+	"linkfast/read-api/utils/res"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jinzhu/copier"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+	maxSearchLength  = 200
+)
+
+// List pages through the links from the newest to the oldest. The next_cursor of a page is
+// passed back as ?cursor= to get the following one. Users other than admins only see their
+// own links, whatever owner_id they ask for.
+func (h *linkHandler) List(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	query, err := parseListQuery(c)
+	if err != nil {
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   err.Error(),
+			Code:      fiber.StatusBadRequest,
+			Status:    false,
+			Message:   "Invalid list query",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(res)
+	}
+
+	if user := middlewares.CurrentUser(c); user != nil && !user.IsAdmin() {
+		query.OwnerID = user.UserID
+	}
+
+	links, next, err := h.service.List(c.Context(), query)
+	if err != nil {
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   err.Error(),
+			Code:      fiber.StatusInternalServerError,
+			Status:    false,
+			Message:   "Error internal in server! Try again later",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(res)
+	}
+
+	page := dtos.LinkPageDto{Links: make([]dtos.LinkDto, len(links))}
+	for i := range links {
+		if err := copier.Copy(&page.Links[i], &links[i]); err != nil {
+			res := res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err.Error(),
+				Code:      fiber.StatusInternalServerError,
+				Status:    false,
+				Message:   "Error internal in server! Try again later",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			}
+
+			return c.Status(fiber.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	if next > 0 {
+		page.NextCursor = strconv.FormatInt(next, 10)
+	}
+
+	res := res.ResponseHttp[dtos.LinkPageDto]{
+		Timestamp: time.Now(),
+		Payload:   page,
+		Code:      fiber.StatusOK,
+		Status:    true,
+		Message:   "Links found",
+		Version:   1,
+		TraceID:   traceID,
+		Path:      "",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// parseListQuery reads cursor, limit (1-200, default 50), created_from/created_to (RFC3339 or
+// YYYY-MM-DD), expired (true|false), owner_id, domain and q, a case-insensitive substring of
+// long_url.
+func parseListQuery(c *fiber.Ctx) (dtos.LinkListQueryDto, error) {
+	query := dtos.LinkListQueryDto{Limit: defaultListLimit}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		parsed, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || parsed <= 0 {
+			return query, errors.New("cursor must be the next_cursor of a previous page")
+		}
+		query.Cursor = parsed
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > maxListLimit {
+			return query, errors.New("limit must be between 1 and " + strconv.Itoa(maxListLimit))
+		}
+		query.Limit = parsed
+	}
+
+	if from := c.Query("created_from"); from != "" {
+		parsed, err := parseStatsTime(from, false)
+		if err != nil {
+			return query, errors.New("created_from must be RFC3339 or YYYY-MM-DD")
+		}
+		query.CreatedFrom = &parsed
+	}
+
+	if to := c.Query("created_to"); to != "" {
+		parsed, err := parseStatsTime(to, true)
+		if err != nil {
+			return query, errors.New("created_to must be RFC3339 or YYYY-MM-DD")
+		}
+		query.CreatedTo = &parsed
+	}
+
+	if query.CreatedFrom != nil && query.CreatedTo != nil && query.CreatedFrom.After(*query.CreatedTo) {
+		return query, errors.New("created_from must be before created_to")
+	}
+
+	if expired := c.Query("expired"); expired != "" {
+		parsed, err := strconv.ParseBool(expired)
+		if err != nil {
+			return query, errors.New("expired must be true or false")
+		}
+		query.Expired = &parsed
+	}
+
+	if owner := c.Query("owner_id"); owner != "" {
+		parsed, err := strconv.ParseInt(owner, 10, 64)
+		if err != nil || parsed <= 0 {
+			return query, errors.New("owner_id must be a user id")
+		}
+		query.OwnerID = parsed
+	}
+
+	query.Domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(c.Query("domain"))), "www.")
+
+	query.Search = strings.TrimSpace(c.Query("q"))
+	if len(query.Search) > maxSearchLength {
+		return query, errors.New("q must be at most " + strconv.Itoa(maxSearchLength) + " characters")
+	}
+
+	return query, nil
+}
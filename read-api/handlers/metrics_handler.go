@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"linkfast/read-api/metrics"
+	"linkfast/read-api/middlewares"
+	"linkfast/read-api/utils/res"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MetricsHandler interface {
+	Get(c *fiber.Ctx) error
+}
+
+type metricsHandler struct{}
+
+func NewMetricsHandler() MetricsHandler {
+	return &metricsHandler{}
+}
+
+// Get exposes the cache and fallback counters, which are operator data, so users other than
+// admins are refused.
+func (h *metricsHandler) Get(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	if user := middlewares.CurrentUser(c); user != nil && !user.IsAdmin() {
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      fiber.StatusForbidden,
+			Status:    false,
+			Message:   "Metrics are limited to API keys and admins",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(res)
+	}
+
+	res := res.ResponseHttp[map[string]int64]{
+		Timestamp: time.Now(),
+		Payload:   metrics.Snapshot(),
+		Code:      fiber.StatusOK,
+		Status:    true,
+		Message:   "Metrics",
+		Version:   1,
+		TraceID:   traceID,
+		Path:      "",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"linkfast/read-api/dtos"
+	"linkfast/read-api/middlewares"
+	"linkfast/read-api/models"
+	"linkfast/read-api/services"
+	"linkfast/read-api/utils/consts"
+	"linkfast/read-api/utils/res"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var maxStatsRange = map[string]time.Duration{
+	models.GranularityHour: 31 * 24 * time.Hour,
+	models.GranularityDay:  366 * 24 * time.Hour,
+}
+
+var defaultStatsRange = map[string]time.Duration{
+	models.GranularityHour: 24 * time.Hour,
+	models.GranularityDay:  30 * 24 * time.Hour,
+}
+
+type StatsHandler interface {
+	GetByLinkID(c *fiber.Ctx) error
+}
+
+type statsHandler struct {
+	service services.StatsService
+}
+
+func NewStatsHandler(service services.StatsService) StatsHandler {
+	return &statsHandler{service: service}
+}
+
+func (h *statsHandler) GetByLinkID(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	id, err := c.ParamsInt("id", 0)
+	if err != nil || id <= 0 {
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   c.Params("id"),
+			Code:      fiber.StatusBadRequest,
+			Status:    false,
+			Message:   "Id is required",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(res)
+	}
+
+	query, err := parseStatsQuery(c)
+	if err != nil {
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   err.Error(),
+			Code:      fiber.StatusBadRequest,
+			Status:    false,
+			Message:   "Invalid stats query",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(res)
+	}
+
+	if user := middlewares.CurrentUser(c); user != nil && !user.IsAdmin() {
+		query.OwnerID = user.UserID
+	}
+
+	stats, err := h.service.GetByLinkID(c.Context(), int64(id), query)
+	if err != nil {
+		if errors.Is(err, consts.ErrForbidden) {
+			return forbidden(c, traceID)
+		}
+
+		if errors.Is(err, consts.ErrRecordNotFound) {
+			res := res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   "",
+				Code:      fiber.StatusNotFound,
+				Status:    false,
+				Message:   "Link not found",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			}
+
+			return c.Status(fiber.StatusNotFound).JSON(res)
+		}
+
+		res := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   err.Error(),
+			Code:      fiber.StatusInternalServerError,
+			Status:    false,
+			Message:   "Error internal in server! Try again later",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(res)
+	}
+
+	res := res.ResponseHttp[dtos.LinkStatsDto]{
+		Timestamp: time.Now(),
+		Payload:   stats,
+		Code:      fiber.StatusOK,
+		Status:    true,
+		Message:   "Link stats found",
+		Version:   1,
+		TraceID:   traceID,
+		Path:      "",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// parseStatsQuery reads granularity (hour|day, default day) and the from/to range, given as
+// RFC3339 or YYYY-MM-DD, defaulting to the last day (hour) or the last 30 days (day).
+func parseStatsQuery(c *fiber.Ctx) (dtos.StatsQueryDto, error) {
+	query := dtos.StatsQueryDto{Granularity: c.Query("granularity", models.GranularityDay)}
+
+	maxRange, ok := maxStatsRange[query.Granularity]
+	if !ok {
+		return query, errors.New("granularity must be hour or day")
+	}
+
+	query.To = time.Now().UTC()
+	if to := c.Query("to"); to != "" {
+		parsed, err := parseStatsTime(to, true)
+		if err != nil {
+			return query, errors.New("to must be RFC3339 or YYYY-MM-DD")
+		}
+		query.To = parsed
+	}
+
+	query.From = query.To.Add(-defaultStatsRange[query.Granularity])
+	if from := c.Query("from"); from != "" {
+		parsed, err := parseStatsTime(from, false)
+		if err != nil {
+			return query, errors.New("from must be RFC3339 or YYYY-MM-DD")
+		}
+		query.From = parsed
+	}
+
+	if query.From.After(query.To) {
+		return query, errors.New("from must be before to")
+	}
+
+	if query.To.Sub(query.From) > maxRange {
+		return query, errors.New("range too large for granularity " + query.Granularity)
+	}
+
+	return query, nil
+}
+
+// parseStatsTime treats a plain date used as the end of the range as the whole day.
+func parseStatsTime(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil || !endOfDay {
+		return parsed, err
+	}
+
+	return parsed.Add(24*time.Hour - time.Nanosecond), nil
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The form has no action so it posts back to the short link URL it was served from.
+var unlockPage = template.Must(template.New("unlock").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Protected link</title>
+<style>
+body { font-family: sans-serif; display: flex; justify-content: center; margin-top: 15vh; }
+form { display: flex; flex-direction: column; gap: 0.75rem; width: 18rem; }
+.error { color: #b00020; }
+</style>
+</head>
+<body>
+<form method="post">
+<h1>Protected link</h1>
+<p>Enter the password to continue.</p>
+{{if .}}<p class="error">{{.}}</p>{{end}}
+<input type="password" name="password" autocomplete="current-password" required autofocus>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+func renderUnlockPage(c *fiber.Ctx, status int, errorMessage string) error {
+	var page bytes.Buffer
+	if err := unlockPage.Execute(&page, errorMessage); err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	c.Set("X-Frame-Options", "DENY")
+	return c.Status(status).Send(page.Bytes())
+}
@@ -0,0 +1,97 @@
+package invalidation
+
+import (
+	"encoding/json"
+	"fmt"
+	"linkfast/read-api/configs"
+	"linkfast/read-api/repositories"
+	"log"
+	"os"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const retryDelay = 5 * time.Second
+
+type Event struct {
+	Op        string `json:"op"`
+	ID        int64  `json:"id"`
+	ShortCode string `json:"short_code"`
+}
+
+// Listen applies the invalidations published by url-projector to the local cache. Every
+// read-api instance uses its own consumer group so all of them receive every event, and
+// starts from the latest offset since older events concern entries it never cached.
+func Listen(cfg configs.CacheConfig, linkCache repositories.LinkCache) {
+	groupID := GroupID(cfg)
+
+	for {
+		consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+			"bootstrap.servers":  cfg.Brokers,
+			"group.id":           groupID,
+			"auto.offset.reset":  "latest",
+			"enable.auto.commit": true,
+		})
+		if err != nil {
+			log.Printf("Failed to create the cache invalidation consumer: %v. Retrying in %s...", err, retryDelay)
+			time.Sleep(retryDelay)
+			continue
+		}
+
+		if err := consumer.Subscribe(cfg.InvalidationTopic, nil); err != nil {
+			consumer.Close()
+			log.Printf("Failed to subscribe to %s: %v. Retrying in %s...", cfg.InvalidationTopic, err, retryDelay)
+			time.Sleep(retryDelay)
+			continue
+		}
+
+		log.Printf("Listening to cache invalidations on %s", cfg.InvalidationTopic)
+		listenLoop(consumer, linkCache)
+
+		consumer.Close()
+		time.Sleep(retryDelay)
+	}
+}
+
+// GroupID returns the configured consumer group, or one named after the host so a restarted
+// instance rejoins its own group instead of leaving an abandoned one behind.
+func GroupID(cfg configs.CacheConfig) string {
+	if cfg.InvalidationGroupID != "" {
+		return cfg.InvalidationGroupID
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		log.Printf("WARN: Could not read the hostname for the cache invalidation group: %v", err)
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("read_api_cache_%s", hostname)
+}
+
+func listenLoop(consumer *kafka.Consumer, linkCache repositories.LinkCache) {
+	for {
+		msg, err := consumer.ReadMessage(time.Second)
+		if err != nil {
+			if kafkaErr, ok := err.(kafka.Error); ok {
+				if kafkaErr.Code() == kafka.ErrAllBrokersDown || kafkaErr.IsFatal() {
+					log.Printf("Fatal cache invalidation consumer error, reconnecting: %v", err)
+					return
+				}
+				if kafkaErr.Code() != kafka.ErrTimedOut {
+					log.Printf("Cache invalidation consumer error: %v", err)
+				}
+			}
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			log.Printf("WARN: Skipping unparseable cache invalidation %v: %v", msg.TopicPartition, err)
+			continue
+		}
+
+		linkCache.Invalidate(event.ID, event.ShortCode)
+	}
+}
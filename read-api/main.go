@@ -3,12 +3,23 @@ package main
 import (
 	"context"
 	"linkfast/read-api/configs"
+	"linkfast/read-api/events"
+	"linkfast/read-api/geo"
 	"linkfast/read-api/handlers"
+	"linkfast/read-api/invalidation"
+	"linkfast/read-api/metrics"
+	"linkfast/read-api/middlewares"
 	"linkfast/read-api/repositories"
 	"linkfast/read-api/routers"
 	"linkfast/read-api/services"
+	"linkfast/read-api/throttle"
 	"linkfast/read-api/utils/envs"
+	"linkfast/shared/auth"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -24,7 +35,7 @@ func main() {
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowMethods: "GET,HEAD,OPTIONS",
+		AllowMethods: "GET,HEAD,POST,OPTIONS",
 		AllowHeaders: "*",
 	}))
 
@@ -32,6 +43,8 @@ func main() {
 	mongoURI := envs.GetEnvWithFallback("MONGO_URI", "")
 	mongoDBName := envs.GetEnvWithFallback("MONGO_DB_NAME", "")
 	apiHost := envs.GetEnvWithFallback("API_HOST", "")
+	expiredLinkURL := envs.GetEnvWithFallback("EXPIRED_LINK_URL", "")
+	kafkaBrokers := envs.GetEnvWithFallback("KAFKA_BROKERS", "")
 
 	required := map[string]string{
 		"MONGO_URI":     mongoURI,
@@ -66,12 +79,143 @@ func main() {
 		ContextKey: "trace_id",
 	}))
 
+	clickCfg := configs.ClickEventsConfig{
+		Brokers:       kafkaBrokers,
+		Topic:         envs.GetEnvWithFallback("CLICK_EVENTS_TOPIC", "link_fast.clicks"),
+		BufferSize:    envs.GetEnvAsIntWithFallback("CLICK_EVENTS_BUFFER_SIZE", 10000),
+		IPHashSalt:    envs.GetEnvWithFallback("CLICK_IP_HASH_SALT", ""),
+		CountryHeader: envs.GetEnvWithFallback("CLICK_COUNTRY_HEADER", ""),
+	}
+
+	clickPublisher := events.NewNoopClickPublisher()
+	if kafkaBrokers != "" {
+		clickPublisher, err = events.NewClickPublisher(clickCfg)
+		if err != nil {
+			log.Fatalf("Critical failure creating the click events producer: %v", err)
+		}
+		log.Printf("Click events will be published to %s", clickCfg.Topic)
+	} else {
+		log.Print("KAFKA_BROKERS not defined, click tracking disabled")
+	}
+	defer clickPublisher.Close()
+	metrics.RegisterGauge("click_events_dropped", clickPublisher.Dropped)
+
 	mongoDB := mongoClient.Database(mongoDBName)
 	linkRepo := repositories.NewLinkRepository(mongoDB)
-	linkService := services.NewLinkService(linkRepo)
-	linkHandler := handlers.NewLinkHandler(linkService)
 
-	routers.LinkRoute(app, linkHandler)
+	cacheCfg := configs.CacheConfig{
+		Capacity:            envs.GetEnvAsIntWithFallback("LINK_CACHE_CAPACITY", 10000),
+		TTL:                 time.Duration(envs.GetEnvAsIntWithFallback("LINK_CACHE_TTL_SECONDS", 60)) * time.Second,
+		NegativeTTL:         time.Duration(envs.GetEnvAsIntWithFallback("LINK_CACHE_NEGATIVE_TTL_SECONDS", 5)) * time.Second,
+		Brokers:             kafkaBrokers,
+		InvalidationTopic:   envs.GetEnvWithFallback("LINK_INVALIDATION_TOPIC", "link_fast.link_invalidations"),
+		InvalidationGroupID: envs.GetEnvWithFallback("LINK_INVALIDATION_GROUP_ID", ""),
+	}
+
+	redirectRepo := linkRepo
+
+	fallbackCfg := configs.FallbackConfig{
+		PostgresURL:  envs.GetEnvWithFallback("FALLBACK_PG_URL", ""),
+		Window:       time.Duration(envs.GetEnvAsIntWithFallback("FALLBACK_WINDOW_SECONDS", 120)) * time.Second,
+		QueryTimeout: time.Duration(envs.GetEnvAsIntWithFallback("FALLBACK_QUERY_TIMEOUT_MS", 300)) * time.Millisecond,
+		MaxConns:     int32(envs.GetEnvAsIntWithFallback("FALLBACK_PG_MAX_CONNS", 4)),
+	}
+
+	if fallbackCfg.PostgresURL != "" {
+		fallbackPool, err := configs.ConnectFallbackPostgres(fallbackCfg)
+		if err != nil {
+			log.Fatalf("Critical failure configuring the Postgres fallback: %v", err)
+		}
+		defer fallbackPool.Close()
+
+		redirectRepo = repositories.NewFallbackLinkRepository(redirectRepo, fallbackPool, fallbackCfg)
+		log.Printf("Postgres fallback enabled for links created in the last %s", fallbackCfg.Window)
+	}
+
+	if cacheCfg.Capacity > 0 {
+		cachedRepo := repositories.NewCachedLinkRepository(redirectRepo, cacheCfg)
+		redirectRepo = cachedRepo
+
+		if kafkaBrokers != "" {
+			go invalidation.Listen(cacheCfg, cachedRepo)
+		} else {
+			log.Print("KAFKA_BROKERS not defined, cached links are only refreshed by TTL")
+		}
+	}
+
+	locator, err := geo.NewLocator(configs.GeoConfig{
+		DatabasePath:   envs.GetEnvWithFallback("GEOIP_DB_PATH", ""),
+		TrustedProxies: strings.Split(envs.GetEnvWithFallback("TRUSTED_PROXIES", ""), ","),
+	})
+	if err != nil {
+		log.Fatalf("Critical failure configuring geo targeting: %v", err)
+	}
+	defer locator.Close()
+
+	linkService := services.NewLinkService(redirectRepo)
+	linkHandler := handlers.NewLinkHandler(linkService, configs.RedirectConfig{
+		ExpiredURL:          expiredLinkURL,
+		PendingURL:          envs.GetEnvWithFallback("PENDING_LINK_URL", ""),
+		VariantCookieMaxAge: time.Duration(envs.GetEnvAsIntWithFallback("VARIANT_COOKIE_MAX_AGE_SECONDS", 2592000)) * time.Second,
+		PermanentMaxAge:     time.Duration(envs.GetEnvAsIntWithFallback("PERMANENT_REDIRECT_MAX_AGE_SECONDS", 86400)) * time.Second,
+	}, clickPublisher, clickCfg, throttle.NewLimiter(
+		envs.GetEnvAsIntWithFallback("UNLOCK_MAX_ATTEMPTS", 5),
+		time.Duration(envs.GetEnvAsIntWithFallback("UNLOCK_WINDOW_SECONDS", 900))*time.Second,
+		100000,
+	), locator)
+
+	statsRepo := repositories.NewStatsRepository(mongoDB)
+	statsService := services.NewStatsService(statsRepo, linkRepo)
+	statsHandler := handlers.NewStatsHandler(statsService)
+
+	authCfg := configs.AuthConfig{
+		JWTSecret:    []byte(envs.GetEnvWithFallback("JWT_SECRET", "")),
+		PostgresURL:  envs.GetEnvWithFallback("AUTH_PG_URL", ""),
+		QueryTimeout: time.Duration(envs.GetEnvAsIntWithFallback("AUTH_QUERY_TIMEOUT_MS", 300)) * time.Millisecond,
+		MaxConns:     int32(envs.GetEnvAsIntWithFallback("AUTH_PG_MAX_CONNS", 2)),
+	}
+
+	var tokens *auth.TokenIssuer
+	if len(authCfg.JWTSecret) > 0 {
+		tokens = auth.NewTokenIssuer(authCfg.JWTSecret, 0)
+	} else {
+		log.Print("JWT_SECRET not defined, bearer tokens are refused")
+	}
 
-	log.Fatal(app.Listen(":" + apiHost))
+	var apiKeyRepo repositories.ApiKeyRepository
+	if authCfg.PostgresURL != "" {
+		authPool, err := configs.ConnectAuthPostgres(authCfg)
+		if err != nil {
+			log.Fatalf("Critical failure configuring the API key lookup: %v", err)
+		}
+		defer authPool.Close()
+
+		apiKeyRepo = repositories.NewApiKeyRepository(authPool, authCfg.QueryTimeout)
+	} else {
+		log.Print("AUTH_PG_URL not defined, API keys are refused")
+	}
+
+	authenticate := middlewares.Authenticate(tokens, apiKeyRepo)
+	routers.LinkRoute(app, linkHandler, statsHandler, authenticate)
+	routers.MetricsRoute(app, handlers.NewMetricsHandler(), authenticate)
+
+	go shutdownOnSignal(app, time.Duration(envs.GetEnvAsIntWithFallback("SHUTDOWN_TIMEOUT_SECONDS", 5))*time.Second)
+
+	if err := app.Listen(":" + apiHost); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("HTTP server stopped, flushing click events and closing connections...")
+}
+
+// shutdownOnSignal stops the server on SIGINT or SIGTERM once the requests in flight finish, so
+// Listen returns and the deferred closes in main flush what they still hold.
+func shutdownOnSignal(app *fiber.App, timeout time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	log.Printf("Received %s, shutting down the HTTP server...", <-signals)
+	if err := app.ShutdownWithTimeout(timeout); err != nil {
+		log.Printf("Error shutting down the HTTP server: %v", err)
+	}
 }
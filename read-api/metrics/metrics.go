@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type Counter struct {
+	value atomic.Int64
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+func (c *Counter) Add(n int64) {
+	c.value.Add(n)
+}
+
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+var (
+	mu       sync.RWMutex
+	counters = map[string]*Counter{}
+	gauges   = map[string]func() int64{}
+)
+
+// NewCounter returns the counter registered under name, creating it on first use.
+func NewCounter(name string) *Counter {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if counter, ok := counters[name]; ok {
+		return counter
+	}
+
+	counter := &Counter{}
+	counters[name] = counter
+	return counter
+}
+
+// RegisterGauge exposes a value that is read at snapshot time, such as a cache size.
+func RegisterGauge(name string, read func() int64) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	gauges[name] = read
+}
+
+func Snapshot() map[string]int64 {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	snapshot := make(map[string]int64, len(counters)+len(gauges))
+	for name, counter := range counters {
+		snapshot[name] = counter.Value()
+	}
+	for name, read := range gauges {
+		snapshot[name] = read()
+	}
+	return snapshot
+}
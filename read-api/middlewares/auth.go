@@ -0,0 +1,100 @@
+package middlewares
+
+import (
+	"errors"
+	"linkfast/read-api/models"
+	"linkfast/read-api/repositories"
+	"linkfast/read-api/utils/consts"
+	"linkfast/read-api/utils/res"
+	"linkfast/shared/auth"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	ApiKeyHeader = "X-API-Key"
+	apiKeyLocal  = "api_key"
+	userLocal    = "user"
+)
+
+// Authenticate accepts the credentials issued by the write API: an API key with the
+// links:read scope in the X-API-Key header, or a user token in "Authorization: Bearer". A nil
+// tokens or apiKeys refuses that kind of credential.
+func Authenticate(tokens *auth.TokenIssuer, apiKeys repositories.ApiKeyRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		traceID, ok := c.Locals("trace_id").(string)
+		if !ok {
+			traceID = "unknown_trace"
+		}
+
+		if plain := c.Get(ApiKeyHeader); plain != "" {
+			if apiKeys == nil {
+				return reject(c, traceID, fiber.StatusUnauthorized, "Invalid API key")
+			}
+
+			key, err := apiKeys.GetByHash(c.Context(), auth.HashApiKey(plain))
+			if errors.Is(err, consts.ErrRecordNotFound) || (err == nil && key.IsRevoked()) {
+				return reject(c, traceID, fiber.StatusUnauthorized, "Invalid API key")
+			}
+
+			if err != nil {
+				return reject(c, traceID, fiber.StatusInternalServerError, "Error internal in server! Try again later")
+			}
+
+			if !key.HasScope(consts.ScopeLinksRead) {
+				return reject(c, traceID, fiber.StatusForbidden, "Credentials lack the "+consts.ScopeLinksRead+" scope")
+			}
+
+			c.Locals(apiKeyLocal, &key)
+			return c.Next()
+		}
+
+		token, found := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !found || token == "" {
+			return reject(c, traceID, fiber.StatusUnauthorized, "API key or bearer token is required")
+		}
+
+		if tokens == nil {
+			return reject(c, traceID, fiber.StatusUnauthorized, "Invalid or expired token")
+		}
+
+		identity, err := tokens.Parse(token)
+		if err != nil {
+			return reject(c, traceID, fiber.StatusUnauthorized, "Invalid or expired token")
+		}
+
+		c.Locals(userLocal, &identity)
+		return c.Next()
+	}
+}
+
+// CurrentApiKey returns the key that authenticated the request, or nil when it was a user or
+// the route is not behind Authenticate.
+func CurrentApiKey(c *fiber.Ctx) *models.ApiKey {
+	key, _ := c.Locals(apiKeyLocal).(*models.ApiKey)
+	return key
+}
+
+// CurrentUser returns the user that authenticated the request, or nil when it was an API key
+// or the route is not behind Authenticate.
+func CurrentUser(c *fiber.Ctx) *auth.Identity {
+	user, _ := c.Locals(userLocal).(*auth.Identity)
+	return user
+}
+
+func reject(c *fiber.Ctx, traceID string, code int, message string) error {
+	return c.Status(code).JSON(
+		res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      code,
+			Status:    false,
+			Message:   message,
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
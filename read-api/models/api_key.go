@@ -0,0 +1,21 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// ApiKey is the part of a write API key the read API needs to authorize a request.
+type ApiKey struct {
+	ID        int64
+	Scopes    []string
+	RevokedAt *time.Time
+}
+
+func (k ApiKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+func (k ApiKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -3,9 +3,115 @@ package models
 import "time"
 
 type Link struct {
-	ID         int64      `json:"id" bson:"_id"`
-	SHORT_CODE string     `json:"short_code" bson:"short_code"`
-	LONG_URL   string     `json:"long_url" bson:"long_url"`
-	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
-	ExpiresAt  *time.Time `json:"expires_at" bson:"expires_at"`
+	ID             int64             `json:"id" bson:"_id"`
+	SHORT_CODE     string            `json:"short_code" bson:"short_code"`
+	LONG_URL       string            `json:"long_url" bson:"long_url"`
+	Domain         string            `json:"domain" bson:"domain,omitempty"`
+	CreatedAt      time.Time         `json:"created_at" bson:"created_at"`
+	ExpiresAt      *time.Time        `json:"expires_at" bson:"expires_at"`
+	ActivatesAt    *time.Time        `json:"activates_at" bson:"activates_at,omitempty"`
+	RedirectType   int               `json:"redirect_type" bson:"redirect_type"`
+	PasswordHash   string            `json:"-" bson:"password_hash,omitempty"`
+	MaxClicks      *int              `json:"max_clicks" bson:"max_clicks,omitempty"`
+	ClickCount     int64             `json:"click_count" bson:"click_count"`
+	GeoTargets     map[string]string `json:"geo_targets" bson:"geo_targets,omitempty"`
+	DeviceTargets  map[string]string `json:"device_targets" bson:"device_targets,omitempty"`
+	Variants       []LinkVariant     `json:"variants" bson:"variants,omitempty"`
+	StickyVariants bool              `json:"sticky_variants" bson:"sticky_variants,omitempty"`
+	OwnerID        *int64            `json:"owner_id" bson:"owner_id,omitempty"`
+}
+
+type LinkVariant struct {
+	Name   string `json:"name" bson:"name"`
+	URL    string `json:"url" bson:"url"`
+	Weight int    `json:"weight" bson:"weight"`
+}
+
+func (l Link) PasswordProtected() bool {
+	return l.PasswordHash != ""
+}
+
+// RedirectStatus falls back to 307 for documents projected before redirect_type existed.
+func (l Link) RedirectStatus() int {
+	switch l.RedirectType {
+	case 301, 302, 307, 308:
+		return l.RedirectType
+	default:
+		return 307
+	}
+}
+
+func (l Link) IsPermanentRedirect() bool {
+	status := l.RedirectStatus()
+	return status == 301 || status == 308
+}
+
+func (l Link) IsGeoTargeted() bool {
+	return len(l.GeoTargets) > 0
+}
+
+func (l Link) IsDeviceTargeted() bool {
+	return len(l.DeviceTargets) > 0
+}
+
+func (l Link) HasVariants() bool {
+	return len(l.Variants) > 0
+}
+
+// IsTargeted reports whether the destination depends on the visitor.
+func (l Link) IsTargeted() bool {
+	return l.IsGeoTargeted() || l.IsDeviceTargeted() || l.HasVariants()
+}
+
+func (l Link) Variant(name string) (LinkVariant, bool) {
+	for _, variant := range l.Variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return LinkVariant{}, false
+}
+
+func (l Link) TotalWeight() int {
+	total := 0
+	for _, variant := range l.Variants {
+		total += max(variant.Weight, 0)
+	}
+	return total
+}
+
+// PickVariant maps roll, drawn from [0, TotalWeight()), onto the cumulative weights.
+func (l Link) PickVariant(roll int) LinkVariant {
+	for _, variant := range l.Variants {
+		if roll < max(variant.Weight, 0) {
+			return variant
+		}
+		roll -= max(variant.Weight, 0)
+	}
+	return l.Variants[len(l.Variants)-1]
+}
+
+func (l Link) GeoTarget(country string) (string, bool) {
+	if country == "" {
+		return "", false
+	}
+	target, ok := l.GeoTargets[country]
+	return target, ok
+}
+
+// DeviceTarget prefers an operating system rule over a device class rule, so "ios" wins over "mobile".
+func (l Link) DeviceTarget(os, device string) (string, bool) {
+	if target, ok := l.DeviceTargets[os]; ok {
+		return target, true
+	}
+	target, ok := l.DeviceTargets[device]
+	return target, ok
+}
+
+func (l Link) IsPending(now time.Time) bool {
+	return l.ActivatesAt != nil && l.ActivatesAt.After(now)
+}
+
+func (l Link) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !l.ExpiresAt.After(now)
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+const (
+	GranularityHour = "hour"
+	GranularityDay  = "day"
+)
+
+type LinkStats struct {
+	LinkID      int64            `bson:"link_id"`
+	ShortCode   string           `bson:"short_code"`
+	Granularity string           `bson:"granularity"`
+	Bucket      time.Time        `bson:"bucket"`
+	Clicks      int64            `bson:"clicks"`
+	Referrers   map[string]int64 `bson:"referrers"`
+	Countries   map[string]int64 `bson:"countries"`
+	Devices     map[string]int64 `bson:"devices"`
+	Browsers    map[string]int64 `bson:"browsers"`
+	Variants    map[string]int64 `bson:"variants"`
+	UpdatedAt   time.Time        `bson:"updated_at"`
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"linkfast/read-api/models"
+	"linkfast/read-api/utils/consts"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ApiKeyRepository interface {
+	GetByHash(ctx context.Context, hash string) (models.ApiKey, error)
+}
+
+// apiKeyRepository reads the keys from the write model, so a key revoked there stops working
+// here right away.
+type apiKeyRepository struct {
+	pool    RowQuerier
+	timeout time.Duration
+}
+
+func NewApiKeyRepository(pool RowQuerier, timeout time.Duration) ApiKeyRepository {
+	return &apiKeyRepository{
+		pool:    pool,
+		timeout: timeout,
+	}
+}
+
+const apiKeyByHashQuery = `
+	SELECT id, scopes, revoked_at
+	FROM link_fast_sc.api_keys
+	WHERE key_hash = $1`
+
+func (a *apiKeyRepository) GetByHash(ctx context.Context, hash string) (models.ApiKey, error) {
+	var key models.ApiKey
+
+	queryCtx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	if err := a.pool.QueryRow(queryCtx, apiKeyByHashQuery, hash).Scan(&key.ID, &key.Scopes, &key.RevokedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ApiKey{}, consts.ErrRecordNotFound
+		}
+
+		log.Printf("error reading the api key: %v", err)
+		return models.ApiKey{}, consts.ErrInternal
+	}
+
+	return key, nil
+}
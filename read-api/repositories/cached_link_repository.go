@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"linkfast/read-api/cache"
+	"linkfast/read-api/configs"
+	"linkfast/read-api/metrics"
+	"linkfast/read-api/models"
+	"linkfast/read-api/utils/consts"
+	"time"
+)
+
+type LinkCache interface {
+	// Invalidate drops the cached entries of a link; code may be empty when only the id is known.
+	Invalidate(id int64, code string)
+}
+
+type CachedLinkRepository interface {
+	LinkRepository
+	LinkCache
+}
+
+type cachedLink struct {
+	link  models.Link
+	found bool
+}
+
+var (
+	cacheHits          = metrics.NewCounter("link_cache_hits")
+	cacheMisses        = metrics.NewCounter("link_cache_misses")
+	cacheNegativeHits  = metrics.NewCounter("link_cache_negative_hits")
+	cacheInvalidations = metrics.NewCounter("link_cache_invalidations")
+)
+
+// cachedLinkRepository keeps the hot short codes in memory in front of Mongo. Unknown codes
+// are cached for a shorter time so a link created right after a miss becomes visible quickly.
+type cachedLinkRepository struct {
+	LinkRepository
+	cfg     configs.CacheConfig
+	entries *cache.LRU[string, cachedLink]
+	codes   *cache.LRU[int64, string]
+}
+
+func NewCachedLinkRepository(repo LinkRepository, cfg configs.CacheConfig) CachedLinkRepository {
+	cached := &cachedLinkRepository{
+		LinkRepository: repo,
+		cfg:            cfg,
+		entries:        cache.NewLRU[string, cachedLink](cfg.Capacity),
+		codes:          cache.NewLRU[int64, string](cfg.Capacity),
+	}
+
+	metrics.RegisterGauge("link_cache_size", func() int64 { return int64(cached.entries.Len()) })
+	return cached
+}
+
+func (c *cachedLinkRepository) GetByCode(ctx context.Context, code string) (models.Link, error) {
+	if cached, ok := c.entries.Get(code); ok {
+		if !cached.found {
+			cacheNegativeHits.Inc()
+			return models.Link{}, consts.ErrRecordNotFound
+		}
+
+		cacheHits.Inc()
+		return cached.link, nil
+	}
+
+	cacheMisses.Inc()
+
+	link, err := c.LinkRepository.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, consts.ErrRecordNotFound) {
+			c.entries.Set(code, cachedLink{found: false}, c.cfg.NegativeTTL)
+		}
+		return link, err
+	}
+
+	ttl := c.ttlFor(link, time.Now())
+	c.entries.Set(code, cachedLink{link: link, found: true}, ttl)
+	c.codes.Set(link.ID, code, ttl)
+
+	return link, nil
+}
+
+func (c *cachedLinkRepository) Invalidate(id int64, code string) {
+	if code == "" {
+		code, _ = c.codes.Get(id)
+	}
+	c.codes.Delete(id)
+
+	if code != "" {
+		c.entries.Delete(code)
+		cacheInvalidations.Inc()
+	}
+}
+
+// ttlFor never keeps a link cached past its expiration, so the 410 starts on time.
+func (c *cachedLinkRepository) ttlFor(link models.Link, now time.Time) time.Duration {
+	ttl := c.cfg.TTL
+	if link.ExpiresAt != nil {
+		if untilExpiry := link.ExpiresAt.Sub(now); untilExpiry < ttl {
+			ttl = untilExpiry
+		}
+	}
+	return ttl
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"linkfast/read-api/configs"
+	"linkfast/read-api/metrics"
+	"linkfast/read-api/models"
+	"linkfast/read-api/utils/consts"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	fallbackAttempts = metrics.NewCounter("link_fallback_attempts")
+	fallbackHits     = metrics.NewCounter("link_fallback_hits")
+	fallbackErrors   = metrics.NewCounter("link_fallback_errors")
+)
+
+// RowQuerier is the part of *pgxpool.Pool the Postgres lookups need.
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// fallbackLinkRepository covers the projection lag: a short code missing from Mongo is looked
+// up in the Postgres write model, but only among links created within the configured window,
+// so unknown codes do not turn into a steady load on the write database.
+type fallbackLinkRepository struct {
+	LinkRepository
+	pool RowQuerier
+	cfg  configs.FallbackConfig
+}
+
+func NewFallbackLinkRepository(repo LinkRepository, pool RowQuerier, cfg configs.FallbackConfig) LinkRepository {
+	return &fallbackLinkRepository{
+		LinkRepository: repo,
+		pool:           pool,
+		cfg:            cfg,
+	}
+}
+
+const recentLinkByCodeQuery = `
+	SELECT id, short_code, long_url, created_at, expires_at, activates_at, redirect_type, coalesce(password_hash, ''), max_clicks, geo_targets, device_targets, variants, sticky_variants, owner_id
+	FROM link_fast_sc.links
+	WHERE short_code = $1 AND created_at >= now() - make_interval(secs => $2)`
+
+func (f *fallbackLinkRepository) GetByCode(ctx context.Context, code string) (models.Link, error) {
+	link, err := f.LinkRepository.GetByCode(ctx, code)
+	if !errors.Is(err, consts.ErrRecordNotFound) {
+		return link, err
+	}
+
+	fallbackAttempts.Inc()
+
+	queryCtx, cancel := context.WithTimeout(ctx, f.cfg.QueryTimeout)
+	defer cancel()
+
+	row := f.pool.QueryRow(queryCtx, recentLinkByCodeQuery, code, f.cfg.Window.Seconds())
+	if err := row.Scan(&link.ID, &link.SHORT_CODE, &link.LONG_URL, &link.CreatedAt, &link.ExpiresAt, &link.ActivatesAt, &link.RedirectType, &link.PasswordHash, &link.MaxClicks, &link.GeoTargets, &link.DeviceTargets, &link.Variants, &link.StickyVariants, &link.OwnerID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Link{}, consts.ErrRecordNotFound
+		}
+
+		fallbackErrors.Inc()
+		log.Printf("error on postgres fallback for short code %s: %v", code, err)
+		return models.Link{}, consts.ErrRecordNotFound
+	}
+
+	fallbackHits.Inc()
+	return link, nil
+}
@@ -6,9 +6,13 @@ import (
 	"linkfast/read-api/models"
 	"linkfast/read-api/utils/consts"
 	"log"
+	"regexp"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type LinkRepository interface {
@@ -16,6 +20,22 @@ type LinkRepository interface {
 	ExistsByShortCode(ctx context.Context, code string) (bool, error)
 	GetById(ctx context.Context, id int64) (models.Link, error)
 	ExistsByID(ctx context.Context, id int64) (bool, error)
+	ConsumeClick(ctx context.Context, id int64, maxClicks int) error
+	List(ctx context.Context, filter LinkFilter) ([]models.Link, error)
+}
+
+// LinkFilter selects a page of links ordered from the newest to the oldest id. Zero values
+// disable the corresponding condition.
+type LinkFilter struct {
+	BeforeID    int64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Expired     *bool
+	Domain      string
+	Search      string
+	OwnerID     int64
+	Now         time.Time
+	Limit       int
 }
 
 type linkRepository struct {
@@ -85,3 +105,98 @@ func (l *linkRepository) ExistsByShortCode(ctx context.Context, code string) (bo
 
 	return count > 0, nil
 }
+
+// ConsumeClick increments click_count only while it is below maxClicks, so concurrent
+// redirects can never go past the cap. A link served by the Postgres fallback has no document
+// to count on yet, so its clicks are refused with ErrNotProjected until the projection lands.
+func (l *linkRepository) ConsumeClick(ctx context.Context, id int64, maxClicks int) error {
+	filter := bson.M{
+		"_id":         id,
+		"click_count": bson.M{"$not": bson.M{"$gte": maxClicks}},
+	}
+
+	result, err := l.collection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"click_count": 1}})
+	if err != nil {
+		log.Printf("error consuming click of link %d: %v", id, err)
+		return consts.ErrInternal
+	}
+
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	exists, err := l.ExistsByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return consts.ErrClickLimit
+	}
+
+	return consts.ErrNotProjected
+}
+
+// List pages by _id, which is a snowflake and therefore sorted by creation time. The domain,
+// owner and created_at filters are served by the domain_id, owner_id_id and created_at_id
+// indexes created by the projector; the substring search cannot use an index and only narrows the scan.
+func (l *linkRepository) List(ctx context.Context, filter LinkFilter) ([]models.Link, error) {
+	query := bson.M{}
+
+	if filter.BeforeID > 0 {
+		query["_id"] = bson.M{"$lt": filter.BeforeID}
+	}
+
+	createdAt := bson.M{}
+	if filter.CreatedFrom != nil {
+		createdAt["$gte"] = *filter.CreatedFrom
+	}
+	if filter.CreatedTo != nil {
+		createdAt["$lte"] = *filter.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	if filter.Expired != nil {
+		if *filter.Expired {
+			query["expires_at"] = bson.M{"$lte": filter.Now}
+		} else {
+			query["$or"] = bson.A{
+				bson.M{"expires_at": nil},
+				bson.M{"expires_at": bson.M{"$gt": filter.Now}},
+			}
+		}
+	}
+
+	if filter.Domain != "" {
+		query["domain"] = filter.Domain
+	}
+
+	if filter.OwnerID > 0 {
+		query["owner_id"] = filter.OwnerID
+	}
+
+	if filter.Search != "" {
+		query["long_url"] = primitive.Regex{Pattern: regexp.QuoteMeta(filter.Search), Options: "i"}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := l.collection.Find(ctx, query, opts)
+	if err != nil {
+		log.Printf("error listing links: %v", err)
+		return nil, consts.ErrInternal
+	}
+	defer cursor.Close(ctx)
+
+	links := []models.Link{}
+	if err := cursor.All(ctx, &links); err != nil {
+		log.Printf("error decoding listed links: %v", err)
+		return nil, consts.ErrInternal
+	}
+
+	return links, nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"linkfast/read-api/models"
+	"linkfast/read-api/utils/consts"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type StatsRepository interface {
+	GetByLink(ctx context.Context, linkID int64, granularity string, from, to time.Time) ([]models.LinkStats, error)
+}
+
+type statsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewStatsRepository(db *mongo.Database) StatsRepository {
+	return &statsRepository{
+		collection: db.Collection("link_stats"),
+	}
+}
+
+func (s *statsRepository) GetByLink(ctx context.Context, linkID int64, granularity string, from, to time.Time) ([]models.LinkStats, error) {
+	filter := bson.M{
+		"link_id":     linkID,
+		"granularity": granularity,
+		"bucket":      bson.M{"$gte": from, "$lte": to},
+	}
+
+	cursor, err := s.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "bucket", Value: 1}}))
+	if err != nil {
+		log.Printf("error finding stats for link %d: %v", linkID, err)
+		return nil, consts.ErrInternal
+	}
+	defer cursor.Close(ctx)
+
+	stats := []models.LinkStats{}
+	if err := cursor.All(ctx, &stats); err != nil {
+		log.Printf("error decoding stats for link %d: %v", linkID, err)
+		return nil, consts.ErrInternal
+	}
+
+	return stats, nil
+}
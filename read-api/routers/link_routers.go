@@ -6,9 +6,12 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func LinkRoute(app *fiber.App, linkHandler handlers.LinkHandler) {
+func LinkRoute(app *fiber.App, linkHandler handlers.LinkHandler, statsHandler handlers.StatsHandler, authenticate fiber.Handler) {
 	router := app.Group("/api/v1/links")
 
-	router.Get("/:id/id", linkHandler.GetByID)
+	router.Get("", authenticate, linkHandler.List)
+	router.Get("/:id/id", authenticate, linkHandler.GetByID)
+	router.Get("/:id/stats", authenticate, statsHandler.GetByLinkID)
 	router.Get("/:code", linkHandler.GetByShotCode)
+	router.Post("/:code", linkHandler.Unlock)
 }
@@ -0,0 +1,11 @@
+package routers
+
+import (
+	"linkfast/read-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MetricsRoute(app *fiber.App, metricsHandler handlers.MetricsHandler, authenticate fiber.Handler) {
+	app.Get("/api/v1/metrics", authenticate, metricsHandler.Get)
+}
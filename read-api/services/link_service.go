@@ -2,8 +2,11 @@ package services
 
 import (
 	"context"
+	"linkfast/read-api/dtos"
 	"linkfast/read-api/models"
 	"linkfast/read-api/repositories"
+	"linkfast/read-api/utils/consts"
+	"time"
 )
 
 type LinkService interface {
@@ -11,6 +14,8 @@ type LinkService interface {
 	ExistsByShortCode(ctx context.Context, code string) (bool, error)
 	GetById(ctx context.Context, id int64) (models.Link, error)
 	ExistsByID(ctx context.Context, id int64) (bool, error)
+	ConsumeClick(ctx context.Context, link models.Link) error
+	List(ctx context.Context, query dtos.LinkListQueryDto) ([]models.Link, int64, error)
 }
 
 type linkService struct {
@@ -24,7 +29,22 @@ func NewLinkService(repo repositories.LinkRepository) LinkService {
 }
 
 func (l *linkService) GetByCode(ctx context.Context, code string) (models.Link, error) {
-	return l.repo.GetByCode(ctx, code)
+	link, err := l.repo.GetByCode(ctx, code)
+	if err != nil {
+		return link, err
+	}
+
+	now := time.Now()
+
+	if link.IsPending(now) {
+		return link, consts.ErrLinkPending
+	}
+
+	if link.IsExpired(now) {
+		return link, consts.ErrLinkExpired
+	}
+
+	return link, nil
 }
 
 func (l *linkService) ExistsByShortCode(ctx context.Context, code string) (bool, error) {
@@ -38,3 +58,36 @@ func (l *linkService) GetById(ctx context.Context, id int64) (models.Link, error
 func (l *linkService) ExistsByID(ctx context.Context, id int64) (bool, error) {
 	return l.repo.ExistsByID(ctx, id)
 }
+
+func (l *linkService) ConsumeClick(ctx context.Context, link models.Link) error {
+	if link.MaxClicks == nil {
+		return nil
+	}
+
+	return l.repo.ConsumeClick(ctx, link.ID, *link.MaxClicks)
+}
+
+// List returns a page of links and the cursor of the next page, or 0 when there is none.
+func (l *linkService) List(ctx context.Context, query dtos.LinkListQueryDto) ([]models.Link, int64, error) {
+	links, err := l.repo.List(ctx, repositories.LinkFilter{
+		BeforeID:    query.Cursor,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+		Expired:     query.Expired,
+		Domain:      query.Domain,
+		Search:      query.Search,
+		OwnerID:     query.OwnerID,
+		Now:         time.Now(),
+		Limit:       query.Limit + 1,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(links) <= query.Limit {
+		return links, 0, nil
+	}
+
+	links = links[:query.Limit]
+	return links, links[len(links)-1].ID, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"linkfast/read-api/dtos"
+	"linkfast/read-api/models"
+	"linkfast/read-api/repositories"
+	"linkfast/read-api/utils/consts"
+	"strings"
+	"time"
+)
+
+type StatsService interface {
+	GetByLinkID(ctx context.Context, linkID int64, query dtos.StatsQueryDto) (dtos.LinkStatsDto, error)
+}
+
+type statsService struct {
+	repo     repositories.StatsRepository
+	linkRepo repositories.LinkRepository
+}
+
+func NewStatsService(repo repositories.StatsRepository, linkRepo repositories.LinkRepository) StatsService {
+	return &statsService{
+		repo:     repo,
+		linkRepo: linkRepo,
+	}
+}
+
+func (s *statsService) GetByLinkID(ctx context.Context, linkID int64, query dtos.StatsQueryDto) (dtos.LinkStatsDto, error) {
+	link, err := s.linkRepo.GetById(ctx, linkID)
+	if err != nil {
+		return dtos.LinkStatsDto{}, err
+	}
+
+	if query.OwnerID > 0 && (link.OwnerID == nil || *link.OwnerID != query.OwnerID) {
+		return dtos.LinkStatsDto{}, consts.ErrForbidden
+	}
+
+	from := bucketStart(query.From, query.Granularity)
+	stats, err := s.repo.GetByLink(ctx, linkID, query.Granularity, from, query.To)
+	if err != nil {
+		return dtos.LinkStatsDto{}, err
+	}
+
+	result := dtos.LinkStatsDto{
+		LinkID:      linkID,
+		Granularity: query.Granularity,
+		From:        from,
+		To:          query.To,
+		Totals:      newBreakdown(),
+		Series:      make([]dtos.StatsBucketDto, 0, len(stats)),
+	}
+
+	for _, bucket := range stats {
+		breakdown := newBreakdown()
+		merge(breakdown.Referrers, bucket.Referrers, result.Totals.Referrers)
+		merge(breakdown.Countries, bucket.Countries, result.Totals.Countries)
+		merge(breakdown.Devices, bucket.Devices, result.Totals.Devices)
+		merge(breakdown.Browsers, bucket.Browsers, result.Totals.Browsers)
+		merge(breakdown.Variants, bucket.Variants, result.Totals.Variants)
+
+		result.TotalClicks += bucket.Clicks
+		result.Series = append(result.Series, dtos.StatsBucketDto{
+			Bucket:            bucket.Bucket,
+			Clicks:            bucket.Clicks,
+			StatsBreakdownDto: breakdown,
+		})
+	}
+
+	return result, nil
+}
+
+func bucketStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	if granularity == models.GranularityHour {
+		return t.Truncate(time.Hour)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func newBreakdown() dtos.StatsBreakdownDto {
+	return dtos.StatsBreakdownDto{
+		Referrers: map[string]int64{},
+		Countries: map[string]int64{},
+		Devices:   map[string]int64{},
+		Browsers:  map[string]int64{},
+		Variants:  map[string]int64{},
+	}
+}
+
+// keyUnescaper reverts the escaping the analytics service applies to Mongo field names.
+var keyUnescaper = strings.NewReplacer("%2E", ".", "%24", "$", "%25", "%")
+
+func merge(bucket, source, totals map[string]int64) {
+	for key, count := range source {
+		key = keyUnescaper.Replace(key)
+		bucket[key] += count
+		totals[key] += count
+	}
+}
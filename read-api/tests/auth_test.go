@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/jackc/pgx/v5"
+
+	"linkfast/read-api/handlers"
+	"linkfast/read-api/middlewares"
+	"linkfast/read-api/models"
+	"linkfast/read-api/repositories"
+	"linkfast/read-api/routers"
+	"linkfast/read-api/services"
+	"linkfast/read-api/utils/consts"
+	"linkfast/shared/auth"
+)
+
+type fakeApiKeys map[string]models.ApiKey
+
+func (f fakeApiKeys) GetByHash(ctx context.Context, hash string) (models.ApiKey, error) {
+	key, ok := f[hash]
+	if !ok {
+		return models.ApiKey{}, consts.ErrRecordNotFound
+	}
+	return key, nil
+}
+
+type fakeStatsRepository struct{}
+
+func (fakeStatsRepository) GetByLink(ctx context.Context, linkID int64, granularity string, from, to time.Time) ([]models.LinkStats, error) {
+	return nil, nil
+}
+
+func setupAuthApp(t *testing.T, repo *fakeLinkRepository, tokens *auth.TokenIssuer, keys fakeApiKeys) *fiber.App {
+	app := fiber.New()
+
+	app.Use(requestid.New(requestid.Config{
+		ContextKey: "trace_id",
+	}))
+
+	statsHandler := handlers.NewStatsHandler(services.NewStatsService(fakeStatsRepository{}, repo))
+	routers.LinkRoute(app, newLinkHandler(t, repo), statsHandler, middlewares.Authenticate(tokens, keys))
+
+	return app
+}
+
+func TestAuthenticate(t *testing.T) {
+	alice, bob := int64(10), int64(20)
+	repo := newFakeLinkRepository(
+		models.Link{ID: 1, SHORT_CODE: "alice01", LONG_URL: "https://www.example.com/alice", OwnerID: &alice},
+		models.Link{ID: 2, SHORT_CODE: "bob0001", LONG_URL: "https://www.example.com/bob", OwnerID: &bob},
+	)
+
+	tokens := auth.NewTokenIssuer([]byte("test-secret"), time.Hour)
+	token := func(identity auth.Identity) string {
+		signed, _, err := tokens.Issue(identity)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		return "Bearer " + signed
+	}
+
+	aliceToken := token(auth.Identity{UserID: alice, Role: auth.RoleUser})
+	adminToken := token(auth.Identity{UserID: 1, Role: auth.RoleAdmin})
+	foreignToken, _, _ := auth.NewTokenIssuer([]byte("other-secret"), time.Hour).Issue(auth.Identity{UserID: alice, Role: auth.RoleAdmin})
+
+	revokedAt := time.Now()
+	keys := fakeApiKeys{
+		auth.HashApiKey("lf_reader"):  {ID: 1, Scopes: []string{consts.ScopeLinksRead}},
+		auth.HashApiKey("lf_writer"):  {ID: 2, Scopes: []string{"links:write"}},
+		auth.HashApiKey("lf_revoked"): {ID: 3, Scopes: []string{consts.ScopeLinksRead}, RevokedAt: &revokedAt},
+	}
+
+	app := setupAuthApp(t, repo, tokens, keys)
+
+	tests := []struct {
+		description   string
+		path          string
+		authorization string
+		apiKey        string
+		expectedCode  int
+	}{
+		{description: "Listagem sem credenciais", path: "/api/v1/links", expectedCode: http.StatusUnauthorized},
+		{description: "Token assinado com outro segredo", path: "/api/v1/links", authorization: "Bearer " + foreignToken, expectedCode: http.StatusUnauthorized},
+		{description: "Chave de API desconhecida", path: "/api/v1/links", apiKey: "lf_unknown", expectedCode: http.StatusUnauthorized},
+		{description: "Chave de API revogada", path: "/api/v1/links", apiKey: "lf_revoked", expectedCode: http.StatusUnauthorized},
+		{description: "Chave de API sem o escopo links:read", path: "/api/v1/links", apiKey: "lf_writer", expectedCode: http.StatusForbidden},
+		{description: "Chave de API com o escopo links:read", path: "/api/v1/links", apiKey: "lf_reader", expectedCode: http.StatusOK},
+		{description: "Usuário lê o próprio link", path: "/api/v1/links/1/id", authorization: aliceToken, expectedCode: http.StatusOK},
+		{description: "Usuário não lê o link de outro", path: "/api/v1/links/2/id", authorization: aliceToken, expectedCode: http.StatusForbidden},
+		{description: "Admin lê o link de qualquer usuário", path: "/api/v1/links/2/id", authorization: adminToken, expectedCode: http.StatusOK},
+		{description: "Chave de API lê qualquer link", path: "/api/v1/links/2/id", apiKey: "lf_reader", expectedCode: http.StatusOK},
+		{description: "Estatísticas sem credenciais", path: "/api/v1/links/1/stats", expectedCode: http.StatusUnauthorized},
+		{description: "Usuário vê as estatísticas do próprio link", path: "/api/v1/links/1/stats", authorization: aliceToken, expectedCode: http.StatusOK},
+		{description: "Usuário não vê as estatísticas de outro", path: "/api/v1/links/2/stats", authorization: aliceToken, expectedCode: http.StatusForbidden},
+		{description: "Admin vê as estatísticas de qualquer link", path: "/api/v1/links/2/stats", authorization: adminToken, expectedCode: http.StatusOK},
+		{description: "Redirecionamento continua público", path: "/api/v1/links/bob0001", expectedCode: http.StatusTemporaryRedirect},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			if test.apiKey != "" {
+				req.Header.Set(middlewares.ApiKeyHeader, test.apiKey)
+			}
+
+			resp, body := send(t, app, req)
+			if resp.StatusCode != test.expectedCode {
+				t.Errorf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", test.expectedCode, resp.StatusCode, body)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_ListOwner(t *testing.T) {
+	repo := newFakeLinkRepository()
+	tokens := auth.NewTokenIssuer([]byte("test-secret"), time.Hour)
+	app := setupAuthApp(t, repo, tokens, fakeApiKeys{})
+
+	tests := []struct {
+		description   string
+		identity      auth.Identity
+		expectedOwner int64
+	}{
+		{description: "Usuário só lista os próprios links", identity: auth.Identity{UserID: 10, Role: auth.RoleUser}, expectedOwner: 10},
+		{description: "Admin lista os links do owner_id pedido", identity: auth.Identity{UserID: 1, Role: auth.RoleAdmin}, expectedOwner: 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			signed, _, err := tokens.Issue(test.identity)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/links?owner_id=20", nil)
+			req.Header.Set("Authorization", "Bearer "+signed)
+
+			resp, body := send(t, app, req)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusOK, resp.StatusCode, body)
+			}
+
+			if repo.filter.OwnerID != test.expectedOwner {
+				t.Errorf("owner_id esperado: %d, obtido: %d", test.expectedOwner, repo.filter.OwnerID)
+			}
+		})
+	}
+}
+
+func TestApiKeyRepository_GetByHash(t *testing.T) {
+	tests := []struct {
+		description    string
+		row            fakeRow
+		expectedErr    error
+		expectedScopes int
+	}{
+		{
+			description: "Chave encontrada",
+			row: fakeRow{scan: func(dest ...any) error {
+				*dest[0].(*int64) = 1
+				*dest[1].(*[]string) = []string{consts.ScopeLinksRead}
+				return nil
+			}},
+			expectedScopes: 1,
+		},
+		{
+			description: "Chave desconhecida",
+			row:         fakeRow{scan: func(dest ...any) error { return pgx.ErrNoRows }},
+			expectedErr: consts.ErrRecordNotFound,
+		},
+		{
+			description: "Falha do Postgres",
+			row:         fakeRow{scan: func(dest ...any) error { return context.DeadlineExceeded }},
+			expectedErr: consts.ErrInternal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			querier := &fakeQuerier{row: test.row}
+			repo := repositories.NewApiKeyRepository(querier, 300*time.Millisecond)
+
+			key, err := repo.GetByHash(context.Background(), auth.HashApiKey("lf_key"))
+			if !errors.Is(err, test.expectedErr) || (test.expectedErr == nil && err != nil) {
+				t.Fatalf("Erro esperado: %v, obtido: %v", test.expectedErr, err)
+			}
+
+			if len(key.Scopes) != test.expectedScopes {
+				t.Errorf("Escopos esperados: %d, obtidos: %v", test.expectedScopes, key.Scopes)
+			}
+
+			if querier.args[0] != auth.HashApiKey("lf_key") || !querier.deadline {
+				t.Errorf("Consulta inesperada: args %v, timeout %v", querier.args, querier.deadline)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"linkfast/read-api/configs"
+	"linkfast/read-api/models"
+	"linkfast/read-api/repositories"
+	"linkfast/read-api/utils/consts"
+)
+
+type fakeLinkRepository struct {
+	links   map[string]models.Link
+	err     error
+	lookups map[string]int
+	clicks  map[int64]int
+	filter  repositories.LinkFilter
+	// unprojected holds the ids served by the Postgres fallback, which have no click count yet.
+	unprojected map[int64]bool
+}
+
+func newFakeLinkRepository(links ...models.Link) *fakeLinkRepository {
+	repo := &fakeLinkRepository{links: map[string]models.Link{}, lookups: map[string]int{}, clicks: map[int64]int{}}
+	for _, link := range links {
+		repo.links[link.SHORT_CODE] = link
+	}
+	return repo
+}
+
+func (f *fakeLinkRepository) GetByCode(ctx context.Context, code string) (models.Link, error) {
+	f.lookups[code]++
+
+	if f.err != nil {
+		return models.Link{}, f.err
+	}
+
+	link, ok := f.links[code]
+	if !ok {
+		return models.Link{}, consts.ErrRecordNotFound
+	}
+	return link, nil
+}
+
+func (f *fakeLinkRepository) ExistsByShortCode(ctx context.Context, code string) (bool, error) {
+	_, ok := f.links[code]
+	return ok, nil
+}
+
+func (f *fakeLinkRepository) GetById(ctx context.Context, id int64) (models.Link, error) {
+	for _, link := range f.links {
+		if link.ID == id {
+			return link, nil
+		}
+	}
+	return models.Link{}, consts.ErrRecordNotFound
+}
+
+func (f *fakeLinkRepository) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	_, err := f.GetById(ctx, id)
+	return err == nil, nil
+}
+
+func (f *fakeLinkRepository) ConsumeClick(ctx context.Context, id int64, maxClicks int) error {
+	if f.unprojected[id] {
+		return consts.ErrNotProjected
+	}
+
+	if f.clicks[id] >= maxClicks {
+		return consts.ErrClickLimit
+	}
+
+	f.clicks[id]++
+	return nil
+}
+
+// List records the filter and only applies the cursor and the limit, which is what the
+// service pages on; the other conditions are translated to Mongo by the real repository.
+func (f *fakeLinkRepository) List(ctx context.Context, filter repositories.LinkFilter) ([]models.Link, error) {
+	f.filter = filter
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	links := []models.Link{}
+	for _, link := range f.links {
+		if filter.BeforeID == 0 || link.ID < filter.BeforeID {
+			links = append(links, link)
+		}
+	}
+
+	sort.Slice(links, func(i, j int) bool { return links[i].ID > links[j].ID })
+	if len(links) > filter.Limit {
+		links = links[:filter.Limit]
+	}
+	return links, nil
+}
+
+func TestCachedLinkRepository(t *testing.T) {
+	cfg := configs.CacheConfig{Capacity: 10, TTL: time.Hour, NegativeTTL: time.Hour}
+	ctx := context.Background()
+
+	link := models.Link{ID: 1, SHORT_CODE: "abc1234", LONG_URL: "https://www.example.com/a"}
+
+	tests := []struct {
+		description     string
+		code            string
+		setup           func(fake *fakeLinkRepository)
+		between         func(repo repositories.CachedLinkRepository, fake *fakeLinkRepository)
+		expectedErr     error
+		expectedLookups int
+	}{
+		{
+			description:     "Segunda leitura vem do cache",
+			code:            link.SHORT_CODE,
+			expectedLookups: 1,
+		},
+		{
+			description:     "Código inexistente fica em cache negativo",
+			code:            "missing",
+			expectedErr:     consts.ErrRecordNotFound,
+			expectedLookups: 1,
+		},
+		{
+			description: "Invalidação pelo código força nova leitura",
+			code:        link.SHORT_CODE,
+			between: func(repo repositories.CachedLinkRepository, fake *fakeLinkRepository) {
+				repo.Invalidate(link.ID, link.SHORT_CODE)
+			},
+			expectedLookups: 2,
+		},
+		{
+			description: "Invalidação só pelo id encontra o código em cache",
+			code:        link.SHORT_CODE,
+			between: func(repo repositories.CachedLinkRepository, fake *fakeLinkRepository) {
+				repo.Invalidate(link.ID, "")
+			},
+			expectedLookups: 2,
+		},
+		{
+			description: "Link não fica em cache além da expiração",
+			code:        link.SHORT_CODE,
+			setup: func(fake *fakeLinkRepository) {
+				soon := time.Now().Add(20 * time.Millisecond)
+				expiring := link
+				expiring.ExpiresAt = &soon
+				fake.links[link.SHORT_CODE] = expiring
+			},
+			between: func(repo repositories.CachedLinkRepository, fake *fakeLinkRepository) {
+				time.Sleep(40 * time.Millisecond)
+			},
+			expectedLookups: 2,
+		},
+		{
+			description: "Erro do repositório não é guardado em cache",
+			code:        link.SHORT_CODE,
+			setup: func(fake *fakeLinkRepository) {
+				fake.err = consts.ErrInternal
+			},
+			between: func(repo repositories.CachedLinkRepository, fake *fakeLinkRepository) {
+				fake.err = nil
+			},
+			expectedLookups: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			fake := newFakeLinkRepository(link)
+			if test.setup != nil {
+				test.setup(fake)
+			}
+			repo := repositories.NewCachedLinkRepository(fake, cfg)
+
+			repo.GetByCode(ctx, test.code)
+			if test.between != nil {
+				test.between(repo, fake)
+			}
+
+			_, err := repo.GetByCode(ctx, test.code)
+			if !errors.Is(err, test.expectedErr) || (test.expectedErr == nil && err != nil) {
+				t.Errorf("Erro esperado: %v, obtido: %v", test.expectedErr, err)
+			}
+
+			if fake.lookups[test.code] != test.expectedLookups {
+				t.Errorf("Leituras esperadas no repositório: %d, obtidas: %d", test.expectedLookups, fake.lookups[test.code])
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"linkfast/read-api/configs"
+	"linkfast/read-api/models"
+	"linkfast/read-api/repositories"
+	"linkfast/read-api/utils/consts"
+)
+
+type fakeRow struct {
+	scan func(dest ...any) error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	return r.scan(dest...)
+}
+
+type fakeQuerier struct {
+	row      fakeRow
+	queries  int
+	args     []any
+	deadline bool
+}
+
+func (f *fakeQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.queries++
+	f.args = args
+	_, f.deadline = ctx.Deadline()
+	return f.row
+}
+
+func writeModelRow(id int64, code, url string, owner *int64) fakeRow {
+	return fakeRow{scan: func(dest ...any) error {
+		*dest[0].(*int64) = id
+		*dest[1].(*string) = code
+		*dest[2].(*string) = url
+		*dest[13].(**int64) = owner
+		return nil
+	}}
+}
+
+func TestFallbackLinkRepository_GetByCode(t *testing.T) {
+	cfg := configs.FallbackConfig{Window: 2 * time.Minute, QueryTimeout: 300 * time.Millisecond}
+	projected := models.Link{ID: 1, SHORT_CODE: "abc1234", LONG_URL: "https://www.example.com/projected"}
+	owner := int64(7)
+
+	tests := []struct {
+		description     string
+		code            string
+		mongoErr        error
+		row             fakeRow
+		expectedErr     error
+		expectedURL     string
+		expectedOwner   *int64
+		expectedQueries int
+	}{
+		{
+			description:     "Link projetado não consulta o Postgres",
+			code:            projected.SHORT_CODE,
+			expectedURL:     projected.LONG_URL,
+			expectedQueries: 0,
+		},
+		{
+			description:     "Link ainda não projetado vem do Postgres",
+			code:            "new1234",
+			row:             writeModelRow(2, "new1234", "https://www.example.com/fresh", &owner),
+			expectedURL:     "https://www.example.com/fresh",
+			expectedOwner:   &owner,
+			expectedQueries: 1,
+		},
+		{
+			description:     "Código fora da janela continua não encontrado",
+			code:            "old1234",
+			row:             fakeRow{scan: func(dest ...any) error { return pgx.ErrNoRows }},
+			expectedErr:     consts.ErrRecordNotFound,
+			expectedQueries: 1,
+		},
+		{
+			description:     "Falha do Postgres é tratada como não encontrado",
+			code:            "new1234",
+			row:             fakeRow{scan: func(dest ...any) error { return context.DeadlineExceeded }},
+			expectedErr:     consts.ErrRecordNotFound,
+			expectedQueries: 1,
+		},
+		{
+			description:     "Erro do Mongo é propagado sem consultar o Postgres",
+			code:            projected.SHORT_CODE,
+			mongoErr:        consts.ErrInternal,
+			expectedErr:     consts.ErrInternal,
+			expectedQueries: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			mongo := newFakeLinkRepository(projected)
+			mongo.err = test.mongoErr
+			querier := &fakeQuerier{row: test.row}
+
+			repo := repositories.NewFallbackLinkRepository(mongo, querier, cfg)
+			link, err := repo.GetByCode(context.Background(), test.code)
+
+			if !errors.Is(err, test.expectedErr) || (test.expectedErr == nil && err != nil) {
+				t.Fatalf("Erro esperado: %v, obtido: %v", test.expectedErr, err)
+			}
+
+			if link.LONG_URL != test.expectedURL {
+				t.Errorf("URL esperada: %q, obtida: %q", test.expectedURL, link.LONG_URL)
+			}
+
+			if test.expectedOwner != nil && (link.OwnerID == nil || *link.OwnerID != *test.expectedOwner) {
+				t.Errorf("owner_id esperado: %d, obtido: %v", *test.expectedOwner, link.OwnerID)
+			}
+
+			if querier.queries != test.expectedQueries {
+				t.Fatalf("Consultas esperadas ao Postgres: %d, obtidas: %d", test.expectedQueries, querier.queries)
+			}
+
+			if querier.queries > 0 {
+				if querier.args[0] != test.code || querier.args[1] != cfg.Window.Seconds() {
+					t.Errorf("Parâmetros inesperados da consulta: %v", querier.args)
+				}
+
+				if !querier.deadline {
+					t.Errorf("A consulta de fallback deveria ter um timeout")
+				}
+			}
+		})
+	}
+}
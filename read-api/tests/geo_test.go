@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"testing"
+
+	"linkfast/read-api/configs"
+	"linkfast/read-api/geo"
+)
+
+func TestLocator_ClientIP(t *testing.T) {
+	locator, err := geo.NewLocator(configs.GeoConfig{TrustedProxies: []string{"10.0.0.0/8", " 172.16.0.1 ", ""}})
+	if err != nil {
+		t.Fatalf("Falha ao criar o locator: %v", err)
+	}
+
+	tests := []struct {
+		description  string
+		remoteIP     string
+		forwardedFor string
+		expected     string
+	}{
+		{description: "Sem X-Forwarded-For usa o peer", remoteIP: "203.0.113.7", expected: "203.0.113.7"},
+		{description: "Peer não confiável ignora o cabeçalho", remoteIP: "203.0.113.7", forwardedFor: "198.51.100.1", expected: "203.0.113.7"},
+		{description: "Proxy confiável repassa o cliente", remoteIP: "10.0.0.2", forwardedFor: "198.51.100.1", expected: "198.51.100.1"},
+		{description: "Proxy em IP único confiável", remoteIP: "172.16.0.1", forwardedFor: "198.51.100.1", expected: "198.51.100.1"},
+		{description: "Endereço forjado à esquerda é ignorado", remoteIP: "10.0.0.2", forwardedFor: "1.2.3.4, 198.51.100.1", expected: "198.51.100.1"},
+		{description: "Cadeia de proxies confiáveis", remoteIP: "10.0.0.2", forwardedFor: "198.51.100.1, 10.0.0.3", expected: "198.51.100.1"},
+		{description: "Salto inválido interrompe a busca", remoteIP: "10.0.0.2", forwardedFor: "198.51.100.1, garbage", expected: "10.0.0.2"},
+		{description: "Apenas proxies confiáveis", remoteIP: "10.0.0.2", forwardedFor: "10.0.0.3", expected: "10.0.0.3"},
+		{description: "IPv4 mapeado em IPv6", remoteIP: "::ffff:10.0.0.2", forwardedFor: "198.51.100.1", expected: "198.51.100.1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if got := locator.ClientIP(test.remoteIP, test.forwardedFor); got != test.expected {
+				t.Errorf("IP esperado: %s, obtido: %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestLocator_InvalidTrustedProxy(t *testing.T) {
+	if _, err := geo.NewLocator(configs.GeoConfig{TrustedProxies: []string{"not-an-ip"}}); err == nil {
+		t.Errorf("Esperado erro para proxy confiável inválido")
+	}
+}
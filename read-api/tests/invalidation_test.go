@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"linkfast/read-api/configs"
+	"linkfast/read-api/invalidation"
+)
+
+func TestInvalidation_GroupID(t *testing.T) {
+	configured := invalidation.GroupID(configs.CacheConfig{InvalidationGroupID: "read_api_cache_pod_0"})
+	if configured != "read_api_cache_pod_0" {
+		t.Errorf("Grupo configurado esperado, obtido %s", configured)
+	}
+
+	first := invalidation.GroupID(configs.CacheConfig{})
+	second := invalidation.GroupID(configs.CacheConfig{})
+
+	if first != second {
+		t.Errorf("O grupo padrão deveria ser estável entre reinícios: %s e %s", first, second)
+	}
+
+	if !strings.HasPrefix(first, "read_api_cache_") {
+		t.Errorf("Grupo padrão inesperado: %s", first)
+	}
+}
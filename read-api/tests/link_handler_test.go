@@ -0,0 +1,365 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"golang.org/x/crypto/bcrypt"
+
+	"linkfast/read-api/configs"
+	"linkfast/read-api/events"
+	"linkfast/read-api/geo"
+	"linkfast/read-api/handlers"
+	"linkfast/read-api/models"
+	"linkfast/read-api/services"
+	"linkfast/read-api/throttle"
+)
+
+const unlockAttempts = 3
+
+func newLinkHandler(t *testing.T, repo *fakeLinkRepository) handlers.LinkHandler {
+	return newClickLinkHandler(t, repo, events.NewNoopClickPublisher(), configs.ClickEventsConfig{}, configs.GeoConfig{})
+}
+
+func newClickLinkHandler(t *testing.T, repo *fakeLinkRepository, clicks events.ClickPublisher, clickCfg configs.ClickEventsConfig, geoCfg configs.GeoConfig) handlers.LinkHandler {
+	locator, err := geo.NewLocator(geoCfg)
+	if err != nil {
+		t.Fatalf("Falha ao criar o locator: %v", err)
+	}
+
+	return handlers.NewLinkHandler(services.NewLinkService(repo), configs.RedirectConfig{
+		PermanentMaxAge:     time.Hour,
+		VariantCookieMaxAge: time.Hour,
+	}, clicks, clickCfg, throttle.NewLimiter(unlockAttempts, time.Minute, 100), locator)
+}
+
+func setupApp(t *testing.T, repo *fakeLinkRepository) *fiber.App {
+	return setupHandlerApp(newLinkHandler(t, repo))
+}
+
+func setupHandlerApp(linkHandler handlers.LinkHandler) *fiber.App {
+
+	app := fiber.New()
+
+	app.Use(requestid.New(requestid.Config{
+		ContextKey: "trace_id",
+	}))
+
+	links := app.Group("/api/v1/links")
+	links.Get("", linkHandler.List)
+	links.Get("/:code", linkHandler.GetByShotCode)
+	links.Post("/:code", linkHandler.Unlock)
+
+	return app
+}
+
+func send(t *testing.T, app *fiber.App, req *http.Request) (*http.Response, string) {
+	resp, err := app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	return resp, string(body)
+}
+
+func unlockRequest(code, password string) *http.Request {
+	form := url.Values{"password": {password}}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/links/"+code, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestLinkHandler_Unlock(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret!"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Falha ao gerar o hash: %v", err)
+	}
+
+	protected := models.Link{ID: 1, SHORT_CODE: "prv1234", LONG_URL: "https://www.example.com/private", PasswordHash: string(hash)}
+	public := models.Link{ID: 2, SHORT_CODE: "pub1234", LONG_URL: "https://www.example.com/public"}
+
+	app := setupApp(t, newFakeLinkRepository(protected, public))
+
+	resp, body := send(t, app, httptest.NewRequest(http.MethodGet, "/api/v1/links/prv1234", nil))
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "<form") {
+		t.Fatalf("Esperado o formulário de desbloqueio, obtido %d: %s", resp.StatusCode, body)
+	}
+
+	tests := []struct {
+		description      string
+		code             string
+		password         string
+		expectedCode     int
+		expectedLocation string
+	}{
+		{description: "Senha incorreta", code: "prv1234", password: "wrong", expectedCode: http.StatusUnauthorized},
+		{description: "Senha correta redireciona com 303", code: "prv1234", password: "s3cret!", expectedCode: http.StatusSeeOther, expectedLocation: protected.LONG_URL},
+		{description: "Sucesso zera as tentativas", code: "prv1234", password: "wrong", expectedCode: http.StatusUnauthorized},
+		{description: "Segunda falha após o sucesso", code: "prv1234", password: "wrong", expectedCode: http.StatusUnauthorized},
+		{description: "Terceira falha após o sucesso", code: "prv1234", password: "wrong", expectedCode: http.StatusUnauthorized},
+		{description: "Tentativas esgotadas bloqueiam até a senha correta", code: "prv1234", password: "s3cret!", expectedCode: http.StatusTooManyRequests},
+		{description: "Link sem senha redireciona direto", code: "pub1234", expectedCode: http.StatusSeeOther, expectedLocation: public.LONG_URL},
+		{description: "Link inexistente", code: "missing", password: "s3cret!", expectedCode: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			resp, body := send(t, app, unlockRequest(test.code, test.password))
+
+			if resp.StatusCode != test.expectedCode {
+				t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", test.expectedCode, resp.StatusCode, body)
+			}
+
+			if location := resp.Header.Get("Location"); location != test.expectedLocation {
+				t.Errorf("Location esperado: %q, obtido: %q", test.expectedLocation, location)
+			}
+
+			if test.expectedCode == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+				t.Errorf("Resposta 429 deveria informar Retry-After")
+			}
+
+			if strings.Contains(body, "s3cret!") {
+				t.Errorf("A resposta não deveria conter a senha")
+			}
+		})
+	}
+}
+
+func TestLinkHandler_RedirectCacheControl(t *testing.T) {
+	noStore := "no-store, no-cache, must-revalidate, max-age=0"
+	inTenMinutes := time.Now().Add(10 * time.Minute)
+	maxClicks := 5
+
+	tests := []struct {
+		description   string
+		link          models.Link
+		expectedCode  int
+		expectedCache string
+	}{
+		{description: "307 padrão não é cacheado", link: models.Link{}, expectedCode: http.StatusTemporaryRedirect, expectedCache: noStore},
+		{description: "302 não é cacheado", link: models.Link{RedirectType: 302}, expectedCode: http.StatusFound, expectedCache: noStore},
+		{description: "301 é público", link: models.Link{RedirectType: 301}, expectedCode: http.StatusMovedPermanently, expectedCache: "public, max-age=3600"},
+		{description: "308 é público", link: models.Link{RedirectType: 308}, expectedCode: http.StatusPermanentRedirect, expectedCache: "public, max-age=3600"},
+		{description: "301 com limite de cliques não é cacheado", link: models.Link{RedirectType: 301, MaxClicks: &maxClicks}, expectedCode: http.StatusMovedPermanently, expectedCache: noStore},
+		{description: "301 segmentado é privado", link: models.Link{RedirectType: 301, GeoTargets: map[string]string{"BR": "https://www.example.com/br"}}, expectedCode: http.StatusMovedPermanently, expectedCache: "private, max-age=3600"},
+		{description: "301 não é cacheado além da expiração", link: models.Link{RedirectType: 301, ExpiresAt: &inTenMinutes}, expectedCode: http.StatusMovedPermanently, expectedCache: "public, max-age=599"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			link := test.link
+			link.ID, link.SHORT_CODE, link.LONG_URL = 1, "abc1234", "https://www.example.com/target"
+
+			app := setupApp(t, newFakeLinkRepository(link))
+			resp, body := send(t, app, httptest.NewRequest(http.MethodGet, "/api/v1/links/abc1234", nil))
+
+			if resp.StatusCode != test.expectedCode {
+				t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", test.expectedCode, resp.StatusCode, body)
+			}
+
+			if cache := resp.Header.Get("Cache-Control"); cache != test.expectedCache {
+				t.Errorf("Cache-Control esperado: %q, obtido: %q", test.expectedCache, cache)
+			}
+		})
+	}
+}
+
+func TestLinkHandler_MaxClicks(t *testing.T) {
+	maxClicks := 2
+	capped := models.Link{ID: 1, SHORT_CODE: "cap1234", LONG_URL: "https://www.example.com/capped", MaxClicks: &maxClicks}
+	unlimited := models.Link{ID: 2, SHORT_CODE: "inf1234", LONG_URL: "https://www.example.com/unlimited"}
+	fresh := models.Link{ID: 3, SHORT_CODE: "new1234", LONG_URL: "https://www.example.com/fresh", MaxClicks: &maxClicks}
+
+	repo := newFakeLinkRepository(capped, unlimited, fresh)
+	repo.unprojected = map[int64]bool{fresh.ID: true}
+	app := setupApp(t, repo)
+
+	tests := []struct {
+		description  string
+		code         string
+		expectedCode int
+	}{
+		{description: "Primeiro clique", code: "cap1234", expectedCode: http.StatusTemporaryRedirect},
+		{description: "Último clique disponível", code: "cap1234", expectedCode: http.StatusTemporaryRedirect},
+		{description: "Cliques esgotados", code: "cap1234", expectedCode: http.StatusGone},
+		{description: "Link sem limite", code: "inf1234", expectedCode: http.StatusTemporaryRedirect},
+		{description: "Link limitado ainda não projetado", code: "new1234", expectedCode: http.StatusServiceUnavailable},
+		{description: "Link limitado ainda não projetado continua recusado", code: "new1234", expectedCode: http.StatusServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			resp, body := send(t, app, httptest.NewRequest(http.MethodGet, "/api/v1/links/"+test.code, nil))
+
+			if resp.StatusCode != test.expectedCode {
+				t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", test.expectedCode, resp.StatusCode, body)
+			}
+		})
+	}
+
+	if repo.clicks[capped.ID] != maxClicks {
+		t.Errorf("Cliques consumidos esperados: %d, obtidos: %d", maxClicks, repo.clicks[capped.ID])
+	}
+
+	if _, ok := repo.clicks[unlimited.ID]; ok {
+		t.Errorf("Link sem limite não deveria consumir cliques")
+	}
+
+	if _, ok := repo.clicks[fresh.ID]; ok {
+		t.Errorf("Link ainda não projetado não deveria consumir cliques")
+	}
+}
+
+type recordingClickPublisher struct {
+	events []events.ClickEvent
+}
+
+func (p *recordingClickPublisher) Publish(event events.ClickEvent) bool {
+	p.events = append(p.events, event)
+	return true
+}
+
+func (p *recordingClickPublisher) Dropped() int64 { return 0 }
+
+func (p *recordingClickPublisher) Close() {}
+
+func TestLinkHandler_ClickEventClient(t *testing.T) {
+	link := models.Link{ID: 1, SHORT_CODE: "clk1234", LONG_URL: "https://www.example.com/clicked"}
+	proxied := configs.GeoConfig{TrustedProxies: []string{"0.0.0.0"}}
+
+	tests := []struct {
+		description     string
+		clickCfg        configs.ClickEventsConfig
+		geoCfg          configs.GeoConfig
+		expectedIP      string
+		expectedCountry string
+	}{
+		{
+			description:     "Atrás de proxy confiável usa o X-Forwarded-For",
+			clickCfg:        configs.ClickEventsConfig{IPHashSalt: "salt", CountryHeader: "CF-IPCountry"},
+			geoCfg:          proxied,
+			expectedIP:      "203.0.113.7",
+			expectedCountry: "BR",
+		},
+		{
+			description:     "Sem proxy confiável ignora o X-Forwarded-For",
+			clickCfg:        configs.ClickEventsConfig{IPHashSalt: "salt", CountryHeader: "CF-IPCountry"},
+			expectedIP:      "0.0.0.0",
+			expectedCountry: "BR",
+		},
+		{
+			description: "Sem cabeçalho de país configurado usa o GeoIP e não o cabeçalho",
+			clickCfg:    configs.ClickEventsConfig{IPHashSalt: "salt"},
+			geoCfg:      proxied,
+			expectedIP:  "203.0.113.7",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			clicks := &recordingClickPublisher{}
+			app := setupHandlerApp(newClickLinkHandler(t, newFakeLinkRepository(link), clicks, test.clickCfg, test.geoCfg))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/links/clk1234", nil)
+			req.Header.Set(fiber.HeaderXForwardedFor, "203.0.113.7")
+			req.Header.Set("CF-IPCountry", "BR")
+
+			resp, body := send(t, app, req)
+			if resp.StatusCode != http.StatusTemporaryRedirect {
+				t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusTemporaryRedirect, resp.StatusCode, body)
+			}
+
+			if len(clicks.events) != 1 {
+				t.Fatalf("Eventos de clique esperados: 1, obtidos: %d", len(clicks.events))
+			}
+
+			expected := events.NewClickEvent(events.ClickInput{IP: test.expectedIP}, test.clickCfg.IPHashSalt)
+			event := clicks.events[0]
+
+			if event.IPHash != expected.IPHash || event.IPPrefix != expected.IPPrefix {
+				t.Errorf("IP esperado: %s (%s), obtido: %s (%s)", expected.IPPrefix, expected.IPHash, event.IPPrefix, event.IPHash)
+			}
+
+			if event.Country != test.expectedCountry {
+				t.Errorf("País esperado: %q, obtido: %q", test.expectedCountry, event.Country)
+			}
+		})
+	}
+}
+
+func TestLinkHandler_DeviceTargets(t *testing.T) {
+	link := models.Link{
+		ID:         1,
+		SHORT_CODE: "dev1234",
+		LONG_URL:   "https://www.example.com/default",
+		DeviceTargets: map[string]string{
+			"ios":     "https://apps.apple.com/app/id123",
+			"mobile":  "https://m.example.com",
+			"desktop": "https://www.example.com/desktop",
+		},
+	}
+
+	app := setupApp(t, newFakeLinkRepository(link))
+
+	tests := []struct {
+		description      string
+		userAgent        string
+		expectedLocation string
+	}{
+		{
+			description:      "Regra do sistema operacional vence a da classe",
+			userAgent:        "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			expectedLocation: "https://apps.apple.com/app/id123",
+		},
+		{
+			description:      "Classe do dispositivo sem regra de sistema",
+			userAgent:        "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			expectedLocation: "https://m.example.com",
+		},
+		{
+			description:      "Desktop",
+			userAgent:        "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expectedLocation: "https://www.example.com/desktop",
+		},
+		{
+			description:      "Tablet sem regra usa a URL padrão",
+			userAgent:        "Mozilla/5.0 (Linux; Android 13; SM-X200) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expectedLocation: link.LONG_URL,
+		},
+		{
+			description:      "Bot usa a URL padrão",
+			userAgent:        "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expectedLocation: link.LONG_URL,
+		},
+		{
+			description:      "Sem User-Agent usa a URL padrão",
+			expectedLocation: link.LONG_URL,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/links/dev1234", nil)
+			req.Header.Set("User-Agent", test.userAgent)
+
+			resp, body := send(t, app, req)
+			if resp.StatusCode != http.StatusTemporaryRedirect {
+				t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusTemporaryRedirect, resp.StatusCode, body)
+			}
+
+			if location := resp.Header.Get("Location"); location != test.expectedLocation {
+				t.Errorf("Location esperado: %q, obtido: %q", test.expectedLocation, location)
+			}
+		})
+	}
+}
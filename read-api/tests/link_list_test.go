@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"linkfast/read-api/dtos"
+	"linkfast/read-api/models"
+	"linkfast/read-api/utils/consts"
+	"linkfast/read-api/utils/res"
+)
+
+func listLinks(count int) []models.Link {
+	links := make([]models.Link, count)
+	for i := range links {
+		links[i] = models.Link{
+			ID:         int64(i + 1),
+			SHORT_CODE: fmt.Sprintf("lst%04d", i+1),
+			LONG_URL:   fmt.Sprintf("https://www.example.com/%d", i+1),
+		}
+	}
+	return links
+}
+
+func listPage(t *testing.T, body string) dtos.LinkPageDto {
+	var response res.ResponseHttp[dtos.LinkPageDto]
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("Falha ao decodificar a resposta JSON: %v", err)
+	}
+	return response.Payload
+}
+
+func TestLinkHandler_List_Cursor(t *testing.T) {
+	app := setupApp(t, newFakeLinkRepository(listLinks(5)...))
+
+	var ids []int64
+	path := "/api/v1/links?limit=2"
+
+	for pages := 0; path != ""; pages++ {
+		if pages == 5 {
+			t.Fatalf("Paginação não terminou, ids obtidos: %v", ids)
+		}
+
+		resp, body := send(t, app, httptest.NewRequest(http.MethodGet, path, nil))
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusOK, resp.StatusCode, body)
+		}
+
+		page := listPage(t, body)
+		for _, link := range page.Links {
+			ids = append(ids, link.ID)
+		}
+
+		path = ""
+		if page.NextCursor != "" {
+			path = "/api/v1/links?limit=2&cursor=" + page.NextCursor
+		}
+	}
+
+	if fmt.Sprint(ids) != "[5 4 3 2 1]" {
+		t.Errorf("Esperado todos os links do mais novo ao mais antigo, obtido %v", ids)
+	}
+}
+
+func TestLinkHandler_List_Filters(t *testing.T) {
+	repo := newFakeLinkRepository(listLinks(3)...)
+	app := setupApp(t, repo)
+
+	resp, body := send(t, app, httptest.NewRequest(http.MethodGet,
+		"/api/v1/links?cursor=42&created_from=2026-01-01&created_to=2026-01-31&expired=false&owner_id=7&domain=WWW.Example.com&q=+promo+", nil))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusOK, resp.StatusCode, body)
+	}
+
+	filter := repo.filter
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+
+	switch {
+	case filter.BeforeID != 42:
+		t.Errorf("Cursor esperado: 42, obtido: %d", filter.BeforeID)
+	case filter.Limit != 51:
+		t.Errorf("Limite esperado: 51 (padrão + 1), obtido: %d", filter.Limit)
+	case filter.CreatedFrom == nil || !filter.CreatedFrom.Equal(from):
+		t.Errorf("created_from esperado: %v, obtido: %v", from, filter.CreatedFrom)
+	case filter.CreatedTo == nil || !filter.CreatedTo.Equal(to):
+		t.Errorf("created_to esperado: %v, obtido: %v", to, filter.CreatedTo)
+	case filter.Expired == nil || *filter.Expired:
+		t.Errorf("expired esperado: false, obtido: %v", filter.Expired)
+	case filter.OwnerID != 7:
+		t.Errorf("owner_id esperado: 7, obtido: %d", filter.OwnerID)
+	case filter.Domain != "example.com":
+		t.Errorf("Domínio esperado: example.com, obtido: %q", filter.Domain)
+	case filter.Search != "promo":
+		t.Errorf("Busca esperada: promo, obtida: %q", filter.Search)
+	case filter.Now.IsZero():
+		t.Errorf("Now deveria ser preenchido para o filtro expired")
+	}
+}
+
+func TestLinkHandler_List_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		description string
+		query       string
+	}{
+		{description: "Cursor não numérico", query: "cursor=abc"},
+		{description: "Cursor negativo", query: "cursor=-1"},
+		{description: "Limite zero", query: "limit=0"},
+		{description: "Limite acima do máximo", query: "limit=201"},
+		{description: "Data inválida", query: "created_from=ontem"},
+		{description: "Intervalo invertido", query: "created_from=2026-02-01&created_to=2026-01-01"},
+		{description: "expired inválido", query: "expired=talvez"},
+		{description: "owner_id inválido", query: "owner_id=0"},
+	}
+
+	app := setupApp(t, newFakeLinkRepository())
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			resp, body := send(t, app, httptest.NewRequest(http.MethodGet, "/api/v1/links?"+test.query, nil))
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusBadRequest, resp.StatusCode, body)
+			}
+		})
+	}
+}
+
+func TestLinkHandler_List_RepositoryError(t *testing.T) {
+	repo := newFakeLinkRepository()
+	repo.err = consts.ErrInternal
+	app := setupApp(t, repo)
+
+	resp, body := send(t, app, httptest.NewRequest(http.MethodGet, "/api/v1/links", nil))
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusInternalServerError, resp.StatusCode, body)
+	}
+}
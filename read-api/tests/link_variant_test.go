@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"linkfast/read-api/models"
+)
+
+func variantLink(sticky bool) models.Link {
+	return models.Link{
+		ID:         1,
+		SHORT_CODE: "ab12345",
+		LONG_URL:   "https://www.example.com/default",
+		Variants: []models.LinkVariant{
+			{Name: "a", URL: "https://www.example.com/a", Weight: 70},
+			{Name: "off", URL: "https://www.example.com/off", Weight: 0},
+			{Name: "b", URL: "https://www.example.com/b", Weight: 30},
+		},
+		StickyVariants: sticky,
+	}
+}
+
+func TestLink_PickVariant(t *testing.T) {
+	link := variantLink(false)
+
+	if total := link.TotalWeight(); total != 100 {
+		t.Fatalf("Peso total esperado: 100, obtido: %d", total)
+	}
+
+	tests := []struct {
+		roll     int
+		expected string
+	}{
+		{roll: 0, expected: "a"},
+		{roll: 69, expected: "a"},
+		{roll: 70, expected: "b"},
+		{roll: 99, expected: "b"},
+	}
+
+	for _, test := range tests {
+		if variant := link.PickVariant(test.roll); variant.Name != test.expected {
+			t.Errorf("Sorteio %d: variante esperada %s, obtida %s", test.roll, test.expected, variant.Name)
+		}
+	}
+}
+
+func TestLinkHandler_Variants(t *testing.T) {
+	t.Run("Variante sorteada respeita os pesos", func(t *testing.T) {
+		app := setupApp(t, newFakeLinkRepository(variantLink(false)))
+		seen := map[string]int{}
+
+		for i := 0; i < 200; i++ {
+			resp, _ := send(t, app, httptest.NewRequest(http.MethodGet, "/api/v1/links/ab12345", nil))
+			seen[resp.Header.Get("Location")]++
+
+			if cookie := resp.Header.Get("Set-Cookie"); cookie != "" {
+				t.Fatalf("Link sem variantes fixas não deveria definir cookie: %s", cookie)
+			}
+		}
+
+		if seen["https://www.example.com/off"] > 0 {
+			t.Errorf("Variante com peso zero não deveria ser sorteada")
+		}
+
+		if seen["https://www.example.com/a"] == 0 || seen["https://www.example.com/b"] == 0 {
+			t.Errorf("Ambas as variantes deveriam ser sorteadas: %v", seen)
+		}
+	})
+
+	t.Run("Variante fixa é lembrada pelo cookie", func(t *testing.T) {
+		app := setupApp(t, newFakeLinkRepository(variantLink(true)))
+
+		resp, _ := send(t, app, httptest.NewRequest(http.MethodGet, "/api/v1/links/ab12345", nil))
+		cookies := resp.Cookies()
+		if len(cookies) != 1 || cookies[0].Name != "lf_v_ab12345" || !cookies[0].HttpOnly {
+			t.Fatalf("Esperado um cookie HttpOnly lf_v_ab12345, obtido %v", cookies)
+		}
+
+		first := resp.Header.Get("Location")
+		for i := 0; i < 20; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/links/ab12345", nil)
+			req.AddCookie(cookies[0])
+
+			resp, _ := send(t, app, req)
+			if location := resp.Header.Get("Location"); location != first {
+				t.Fatalf("Visitante com cookie deveria manter a variante %s, obtido %s", first, location)
+			}
+		}
+	})
+
+	t.Run("Cookie com variante desconhecida é sorteado de novo", func(t *testing.T) {
+		app := setupApp(t, newFakeLinkRepository(variantLink(true)))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/links/ab12345", nil)
+		req.AddCookie(&http.Cookie{Name: "lf_v_ab12345", Value: "removed"})
+
+		resp, _ := send(t, app, req)
+		cookies := resp.Cookies()
+		if len(cookies) != 1 || (cookies[0].Value != "a" && cookies[0].Value != "b") {
+			t.Fatalf("Esperado um novo cookie com uma variante válida, obtido %v", cookies)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"linkfast/read-api/cache"
+)
+
+func TestLRU(t *testing.T) {
+	tests := []struct {
+		description string
+		capacity    int
+		run         func(c *cache.LRU[string, int])
+		expected    map[string]int
+		missing     []string
+	}{
+		{
+			description: "Remove a entrada menos usada ao exceder a capacidade",
+			capacity:    2,
+			run: func(c *cache.LRU[string, int]) {
+				c.Set("a", 1, time.Minute)
+				c.Set("b", 2, time.Minute)
+				c.Set("c", 3, time.Minute)
+			},
+			expected: map[string]int{"b": 2, "c": 3},
+			missing:  []string{"a"},
+		},
+		{
+			description: "Leitura torna a entrada a mais recente",
+			capacity:    2,
+			run: func(c *cache.LRU[string, int]) {
+				c.Set("a", 1, time.Minute)
+				c.Set("b", 2, time.Minute)
+				c.Get("a")
+				c.Set("c", 3, time.Minute)
+			},
+			expected: map[string]int{"a": 1, "c": 3},
+			missing:  []string{"b"},
+		},
+		{
+			description: "Regravar uma chave atualiza o valor sem ocupar espaço",
+			capacity:    2,
+			run: func(c *cache.LRU[string, int]) {
+				c.Set("a", 1, time.Minute)
+				c.Set("b", 2, time.Minute)
+				c.Set("a", 10, time.Minute)
+			},
+			expected: map[string]int{"a": 10, "b": 2},
+		},
+		{
+			description: "TTL não positivo remove a entrada",
+			capacity:    2,
+			run: func(c *cache.LRU[string, int]) {
+				c.Set("a", 1, time.Minute)
+				c.Set("a", 2, 0)
+			},
+			missing: []string{"a"},
+		},
+		{
+			description: "Entrada expirada não é retornada",
+			capacity:    2,
+			run: func(c *cache.LRU[string, int]) {
+				c.Set("a", 1, 10*time.Millisecond)
+				c.Set("b", 2, time.Minute)
+				time.Sleep(30 * time.Millisecond)
+			},
+			expected: map[string]int{"b": 2},
+			missing:  []string{"a"},
+		},
+		{
+			description: "Delete remove a entrada",
+			capacity:    2,
+			run: func(c *cache.LRU[string, int]) {
+				c.Set("a", 1, time.Minute)
+				c.Delete("a")
+				c.Delete("unknown")
+			},
+			missing: []string{"a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := cache.NewLRU[string, int](test.capacity)
+			test.run(c)
+
+			for key, want := range test.expected {
+				if got, ok := c.Get(key); !ok || got != want {
+					t.Errorf("Chave %s: esperado %d, obtido %d (presente: %t)", key, want, got, ok)
+				}
+			}
+
+			for _, key := range test.missing {
+				if got, ok := c.Get(key); ok {
+					t.Errorf("Chave %s deveria estar ausente, obtido %d", key, got)
+				}
+			}
+
+			if c.Len() != len(test.expected) {
+				t.Errorf("Tamanho esperado: %d, obtido: %d", len(test.expected), c.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"linkfast/read-api/handlers"
+	"linkfast/read-api/middlewares"
+	"linkfast/read-api/routers"
+	"linkfast/shared/auth"
+)
+
+func TestMetricsRoute(t *testing.T) {
+	tokens := auth.NewTokenIssuer([]byte("test-secret"), time.Hour)
+
+	app := fiber.New()
+	routers.MetricsRoute(app, handlers.NewMetricsHandler(), middlewares.Authenticate(tokens, nil))
+
+	tests := []struct {
+		description  string
+		identity     *auth.Identity
+		expectedCode int
+	}{
+		{description: "Sem credenciais", expectedCode: http.StatusUnauthorized},
+		{description: "Usuário comum", identity: &auth.Identity{UserID: 10, Role: auth.RoleUser}, expectedCode: http.StatusForbidden},
+		{description: "Admin", identity: &auth.Identity{UserID: 1, Role: auth.RoleAdmin}, expectedCode: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil)
+			if test.identity != nil {
+				token, _, err := tokens.Issue(*test.identity)
+				if err != nil {
+					t.Fatalf("Erro inesperado: %v", err)
+				}
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			resp, err := app.Test(req, 3000)
+			if err != nil {
+				t.Fatalf("Erro ao executar a requisição: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != test.expectedCode {
+				t.Errorf("Status code esperado: %d, obtido: %d", test.expectedCode, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"linkfast/read-api/throttle"
+)
+
+func TestLimiter_Reserve(t *testing.T) {
+	tests := []struct {
+		description string
+		maxAttempts int
+		run         func(l *throttle.Limiter)
+		key         string
+		expected    bool
+	}{
+		{
+			description: "Primeira tentativa é permitida",
+			maxAttempts: 3,
+			run:         func(l *throttle.Limiter) {},
+			key:         "abc1234",
+			expected:    true,
+		},
+		{
+			description: "Bloqueia após esgotar as tentativas",
+			maxAttempts: 2,
+			run: func(l *throttle.Limiter) {
+				l.Reserve("abc1234")
+				l.Reserve("abc1234")
+			},
+			key:      "abc1234",
+			expected: false,
+		},
+		{
+			description: "Chaves são contadas separadamente",
+			maxAttempts: 1,
+			run: func(l *throttle.Limiter) {
+				l.Reserve("other12")
+			},
+			key:      "abc1234",
+			expected: true,
+		},
+		{
+			description: "Reset libera a chave",
+			maxAttempts: 1,
+			run: func(l *throttle.Limiter) {
+				l.Reserve("abc1234")
+				l.Reset("abc1234")
+			},
+			key:      "abc1234",
+			expected: true,
+		},
+		{
+			description: "Limite mínimo de uma tentativa",
+			maxAttempts: 0,
+			run:         func(l *throttle.Limiter) {},
+			key:         "abc1234",
+			expected:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			limiter := throttle.NewLimiter(test.maxAttempts, time.Minute, 100)
+			test.run(limiter)
+
+			allowed, retryAfter := limiter.Reserve(test.key)
+			if allowed != test.expected {
+				t.Fatalf("Permitido esperado: %t, obtido: %t", test.expected, allowed)
+			}
+
+			if !allowed && (retryAfter <= 0 || retryAfter > time.Minute) {
+				t.Errorf("Tempo de espera fora da janela: %s", retryAfter)
+			}
+		})
+	}
+}
+
+func TestLimiter_WindowExpires(t *testing.T) {
+	limiter := throttle.NewLimiter(1, 20*time.Millisecond, 100)
+
+	limiter.Reserve("abc1234")
+	if allowed, _ := limiter.Reserve("abc1234"); allowed {
+		t.Fatalf("A segunda tentativa deveria ser bloqueada dentro da janela")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if allowed, _ := limiter.Reserve("abc1234"); !allowed {
+		t.Errorf("A chave deveria ser liberada após a janela")
+	}
+}
+
+func TestLimiter_ConcurrentReservations(t *testing.T) {
+	const maxAttempts = 5
+	limiter := throttle.NewLimiter(maxAttempts, time.Minute, 100)
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if ok, _ := limiter.Reserve("abc1234"); ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	if allowed.Load() != maxAttempts {
+		t.Errorf("Tentativas concorrentes permitidas esperadas: %d, obtidas: %d", maxAttempts, allowed.Load())
+	}
+}
@@ -0,0 +1,58 @@
+package throttle
+
+import (
+	"linkfast/read-api/cache"
+	"sync"
+	"time"
+)
+
+type attempts struct {
+	count   int
+	resetAt time.Time
+}
+
+// Limiter counts attempts per key inside a fixed window and blocks the key once the limit is
+// reached. Keys live in a bounded LRU so a flood of distinct keys cannot exhaust memory.
+type Limiter struct {
+	mu          sync.Mutex
+	maxAttempts int
+	window      time.Duration
+	keys        *cache.LRU[string, attempts]
+}
+
+func NewLimiter(maxAttempts int, window time.Duration, capacity int) *Limiter {
+	return &Limiter{
+		maxAttempts: max(maxAttempts, 1),
+		window:      window,
+		keys:        cache.NewLRU[string, attempts](capacity),
+	}
+}
+
+// Reserve counts an attempt for key before it is checked and reports whether it may proceed
+// or, when it may not, how long it has to wait. Counting first means concurrent attempts cannot
+// all pass the check while the slow comparison of each one is still running; a successful
+// attempt clears the key through Reset.
+func (l *Limiter) Reserve(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current, ok := l.keys.Get(key)
+	if !ok {
+		current = attempts{resetAt: time.Now().Add(l.window)}
+	}
+
+	if current.count >= l.maxAttempts {
+		return false, time.Until(current.resetAt)
+	}
+
+	current.count++
+	l.keys.Set(key, current, time.Until(current.resetAt))
+	return true, 0
+}
+
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.keys.Delete(key)
+}
@@ -8,4 +8,12 @@ var (
 	ErrInternalDB     = errors.New("internal database error.")
 	ErrInternal       = errors.New("internal error in server.")
 	ErrFieldNull      = errors.New("field is null")
+	ErrLinkExpired    = errors.New("link expired")
+	ErrClickLimit     = errors.New("link click limit reached")
+	ErrNotProjected   = errors.New("link not projected yet")
+	ErrLinkPending    = errors.New("link not active yet")
+	ErrForbidden      = errors.New("link belongs to another user")
 )
+
+// ScopeLinksRead is the write API scope an API key needs to read links and their stats.
+const ScopeLinksRead = "links:read"
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const issuer = "linkfast-write-api"
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Identity is what a valid token says about its user. Tokens are not checked against the
+// users table, so a role change takes effect when the user logs in again.
+type Identity struct {
+	UserID int64
+	Role   string
+}
+
+func (i Identity) IsAdmin() bool {
+	return i.Role == RoleAdmin
+}
+
+type claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// TokenIssuer signs and verifies HS256 access tokens. The write API issues them and the read
+// API only parses them, both with the same secret.
+type TokenIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenIssuer(secret []byte, ttl time.Duration) *TokenIssuer {
+	return &TokenIssuer{secret: secret, ttl: ttl}
+}
+
+func (t *TokenIssuer) Issue(identity Identity) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(t.ttl)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Role: identity.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(identity.UserID, 10),
+			Issuer:    issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := token.SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+func (t *TokenIssuer) Parse(token string) (Identity, error) {
+	var parsed claims
+
+	_, err := jwt.ParseWithClaims(token, &parsed, func(token *jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(issuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(parsed.Subject, 10, 64)
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+
+	return Identity{UserID: userID, Role: parsed.Role}, nil
+}
+
+// HashApiKey uses a plain SHA-256: the keys are 256-bit random values, so unlike passwords
+// they need no salt or slow hash, and the hash can be looked up directly.
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,52 @@
+package retry
+
+import (
+	"log"
+	"time"
+)
+
+type Policy struct {
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Permanent reports the errors that are returned right away instead of retried.
+	Permanent func(err error) bool
+	// Sleep waits between attempts and defaults to time.Sleep.
+	Sleep func(d time.Duration)
+}
+
+// Do runs fn up to MaxRetries+1 times, doubling the wait between attempts up to MaxBackoff,
+// and returns the last error.
+func Do(policy Policy, fn func() error) error {
+	sleep := policy.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	backoff := policy.Backoff
+	var err error
+
+	for attempt := 0; attempt <= policy.MaxRetries; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+
+		if policy.Permanent != nil && policy.Permanent(err) {
+			return err
+		}
+
+		if attempt == policy.MaxRetries {
+			break
+		}
+
+		log.Printf("WARN: Attempt %d/%d failed: %v. Retrying in %s...", attempt+1, policy.MaxRetries+1, err, backoff)
+		sleep(backoff)
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"linkfast/shared/auth"
+)
+
+func TestTokenIssuer(t *testing.T) {
+	issuer := auth.NewTokenIssuer([]byte("test-secret"), time.Hour)
+	identity := auth.Identity{UserID: 42, Role: auth.RoleAdmin}
+
+	valid, _, err := issuer.Issue(identity)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	expired, _, err := auth.NewTokenIssuer([]byte("test-secret"), -time.Minute).Issue(identity)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	otherSecret, _, err := auth.NewTokenIssuer([]byte("other-secret"), time.Hour).Issue(identity)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": "42", "iss": "linkfast-write-api", "role": auth.RoleAdmin}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		token       string
+		expectedErr error
+	}{
+		{description: "Token válido", token: valid, expectedErr: nil},
+		{description: "Token expirado", token: expired, expectedErr: auth.ErrInvalidToken},
+		{description: "Token assinado com outro segredo", token: otherSecret, expectedErr: auth.ErrInvalidToken},
+		{description: "Token sem assinatura", token: unsigned, expectedErr: auth.ErrInvalidToken},
+		{description: "Token malformado", token: "not-a-token", expectedErr: auth.ErrInvalidToken},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			parsed, err := issuer.Parse(test.token)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Erro esperado: %v, obtido: %v", test.expectedErr, err)
+			}
+
+			if test.expectedErr == nil && parsed != identity {
+				t.Errorf("Identidade esperada: %+v, obtida: %+v", identity, parsed)
+			}
+		})
+	}
+}
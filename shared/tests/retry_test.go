@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"linkfast/shared/retry"
+)
+
+var errInvalid = errors.New("invalid envelope")
+
+func TestDo(t *testing.T) {
+	errTransient := errors.New("mongo unavailable")
+
+	policy := retry.Policy{
+		MaxRetries: 4,
+		Backoff:    100 * time.Millisecond,
+		MaxBackoff: 300 * time.Millisecond,
+		Permanent:  func(err error) bool { return errors.Is(err, errInvalid) },
+	}
+
+	tests := []struct {
+		description   string
+		policy        retry.Policy
+		failures      int
+		err           error
+		expectedCalls int
+		expectedWaits []time.Duration
+		expectedErr   error
+	}{
+		{
+			description:   "Sucesso na primeira tentativa",
+			policy:        policy,
+			failures:      0,
+			expectedCalls: 1,
+		},
+		{
+			description:   "Sucesso após falhas transitórias",
+			policy:        policy,
+			failures:      2,
+			err:           errTransient,
+			expectedCalls: 3,
+			expectedWaits: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			description:   "Backoff limitado ao máximo até esgotar as tentativas",
+			policy:        policy,
+			failures:      10,
+			err:           errTransient,
+			expectedCalls: 5,
+			expectedWaits: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond},
+			expectedErr:   errTransient,
+		},
+		{
+			description:   "Sem novas tentativas configuradas",
+			policy:        retry.Policy{Backoff: time.Second, MaxBackoff: time.Second},
+			failures:      10,
+			err:           errTransient,
+			expectedCalls: 1,
+			expectedErr:   errTransient,
+		},
+		{
+			description:   "Erro permanente não é repetido",
+			policy:        policy,
+			failures:      10,
+			err:           fmt.Errorf("decode: %w", errInvalid),
+			expectedCalls: 1,
+			expectedErr:   errInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var waits []time.Duration
+			calls := 0
+
+			test.policy.Sleep = func(d time.Duration) { waits = append(waits, d) }
+
+			err := retry.Do(test.policy, func() error {
+				calls++
+				if calls <= test.failures {
+					return test.err
+				}
+				return nil
+			})
+
+			if !errors.Is(err, test.expectedErr) || (test.expectedErr == nil && err != nil) {
+				t.Errorf("Erro esperado: %v, obtido: %v", test.expectedErr, err)
+			}
+
+			if calls != test.expectedCalls {
+				t.Errorf("Tentativas esperadas: %d, obtidas: %d", test.expectedCalls, calls)
+			}
+
+			if !slices.Equal(waits, test.expectedWaits) {
+				t.Errorf("Esperas esperadas: %v, obtidas: %v", test.expectedWaits, waits)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"testing"
+
+	"linkfast/shared/useragent"
+)
+
+func TestParse_UserAgents(t *testing.T) {
+	tests := []struct {
+		description string
+		ua          string
+		expected    useragent.Client
+	}{
+		{
+			description: "Chrome no Windows",
+			ua:          "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected:    useragent.Client{Device: useragent.DeviceDesktop, OS: useragent.OSWindows, Browser: "Chrome"},
+		},
+		{
+			description: "Edge no Windows",
+			ua:          "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			expected:    useragent.Client{Device: useragent.DeviceDesktop, OS: useragent.OSWindows, Browser: "Edge"},
+		},
+		{
+			description: "Safari no iPhone",
+			ua:          "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			expected:    useragent.Client{Device: useragent.DeviceMobile, OS: useragent.OSiOS, Browser: "Safari"},
+		},
+		{
+			description: "Safari no iPad",
+			ua:          "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			expected:    useragent.Client{Device: useragent.DeviceTablet, OS: useragent.OSiOS, Browser: "Safari"},
+		},
+		{
+			description: "Chrome no Android",
+			ua:          "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			expected:    useragent.Client{Device: useragent.DeviceMobile, OS: useragent.OSAndroid, Browser: "Chrome"},
+		},
+		{
+			description: "Tablet Android",
+			ua:          "Mozilla/5.0 (Linux; Android 13; SM-X200) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected:    useragent.Client{Device: useragent.DeviceTablet, OS: useragent.OSAndroid, Browser: "Chrome"},
+		},
+		{
+			description: "Firefox no macOS",
+			ua:          "Mozilla/5.0 (Macintosh; Intel Mac OS X 14.1; rv:120.0) Gecko/20100101 Firefox/120.0",
+			expected:    useragent.Client{Device: useragent.DeviceDesktop, OS: useragent.OSMacOS, Browser: "Firefox"},
+		},
+		{
+			description: "Firefox no Linux",
+			ua:          "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0",
+			expected:    useragent.Client{Device: useragent.DeviceDesktop, OS: useragent.OSLinux, Browser: "Firefox"},
+		},
+		{
+			description: "Chrome no ChromeOS",
+			ua:          "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected:    useragent.Client{Device: useragent.DeviceDesktop, OS: useragent.OSOther, Browser: "Chrome"},
+		},
+		{
+			description: "Windows Phone",
+			ua:          "Mozilla/5.0 (Windows Phone 10.0; Android 6.0.1; Microsoft; Lumia 950) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/52.0.2743.116 Mobile Safari/537.36 Edge/15.15063",
+			expected:    useragent.Client{Device: useragent.DeviceMobile, OS: useragent.OSOther, Browser: "Edge"},
+		},
+		{
+			description: "curl",
+			ua:          "curl/8.4.0",
+			expected:    useragent.Client{Device: useragent.DeviceBot, OS: useragent.OSOther, Browser: useragent.BrowserBot},
+		},
+		{
+			description: "Googlebot",
+			ua:          "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected:    useragent.Client{Device: useragent.DeviceBot, OS: useragent.OSOther, Browser: useragent.BrowserBot},
+		},
+		{
+			description: "User agent vazio",
+			ua:          "",
+			expected:    useragent.Client{Device: useragent.DeviceUnknown, OS: useragent.OSOther, Browser: useragent.BrowserOther},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			client := useragent.Parse(test.ua)
+			if client != test.expected {
+				t.Errorf("Esperado %+v, obtido %+v", test.expected, client)
+			}
+		})
+	}
+}
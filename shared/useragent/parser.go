@@ -0,0 +1,109 @@
+package useragent
+
+import "strings"
+
+// The OS and device values match the device_targets keys accepted by write-api.
+const (
+	OSiOS     = "ios"
+	OSAndroid = "android"
+	OSWindows = "windows"
+	OSMacOS   = "macos"
+	OSLinux   = "linux"
+	OSOther   = "other"
+
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+
+	BrowserBot   = "Bot"
+	BrowserOther = "Other"
+)
+
+type Client struct {
+	Device  string
+	OS      string
+	Browser string
+}
+
+var botMarkers = []string{"bot", "crawler", "spider", "slurp", "curl/", "wget/", "python-requests", "headless", "preview"}
+
+type rule struct {
+	markers []string
+	name    string
+}
+
+// Order matters: Chromium based browsers also announce "chrome/" and "safari/".
+var browserRules = []rule{
+	{markers: []string{"edg/", "edge/", "edga/", "edgios/"}, name: "Edge"},
+	{markers: []string{"opr/", "opera"}, name: "Opera"},
+	{markers: []string{"samsungbrowser"}, name: "Samsung Internet"},
+	{markers: []string{"firefox/", "fxios/"}, name: "Firefox"},
+	{markers: []string{"crios/", "chrome/", "chromium/"}, name: "Chrome"},
+	{markers: []string{"safari/"}, name: "Safari"},
+	{markers: []string{"msie ", "trident/"}, name: "Internet Explorer"},
+}
+
+// Order matters: Android and iOS browsers also announce "linux" and "mac os x".
+var osRules = []rule{
+	{markers: []string{"windows phone"}, name: OSOther},
+	{markers: []string{"windows"}, name: OSWindows},
+	{markers: []string{"iphone", "ipad", "ipod"}, name: OSiOS},
+	{markers: []string{"android"}, name: OSAndroid},
+	{markers: []string{"mac os x", "macintosh"}, name: OSMacOS},
+	{markers: []string{"cros "}, name: OSOther},
+	{markers: []string{"linux"}, name: OSLinux},
+}
+
+// Parse classifies a User-Agent header with simple substring rules, which is enough for the
+// coarse device targeting of read-api and the breakdowns of analytics, and avoids shipping a
+// UA database. Both services share it so they agree on how a visitor is classified.
+func Parse(ua string) Client {
+	lower := strings.ToLower(ua)
+
+	if strings.TrimSpace(lower) == "" {
+		return Client{Device: DeviceUnknown, OS: OSOther, Browser: BrowserOther}
+	}
+
+	if containsAny(lower, botMarkers) {
+		return Client{Device: DeviceBot, OS: OSOther, Browser: BrowserBot}
+	}
+
+	return Client{
+		Device:  device(lower),
+		OS:      match(lower, osRules, OSOther),
+		Browser: match(lower, browserRules, BrowserOther),
+	}
+}
+
+func device(lower string) string {
+	switch {
+	case containsAny(lower, []string{"ipad", "tablet", "kindle", "silk/"}):
+		return DeviceTablet
+	case strings.Contains(lower, "android") && !strings.Contains(lower, "mobile"):
+		return DeviceTablet
+	case containsAny(lower, []string{"mobi", "iphone", "ipod", "android", "windows phone"}):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+func match(lower string, rules []rule, fallback string) string {
+	for _, r := range rules {
+		if containsAny(lower, r.markers) {
+			return r.name
+		}
+	}
+	return fallback
+}
+
+func containsAny(value string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(value, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,15 +1,21 @@
 package cdc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	models "linkfast/url-projector/model"
 	"log"
+	"net/url"
+	"strings"
 	"time"
 )
 
 const timeFormat = "2006-01-02T15:04:05Z"
 
+// Debezium emits this placeholder for unchanged TOAST columns in update events.
+const unavailableValue = "__debezium_unavailable_value"
+
 type Envelope struct {
 	Schema  struct{} `json:"schema"`
 	Payload struct {
@@ -20,16 +26,101 @@ type Envelope struct {
 		TsMs     int64                  `json:"ts_ms"`
 		Sequence string                 `json:"sequence"`
 	} `json:"payload"`
+	Tombstone bool `json:"-"`
+}
+
+type key struct {
+	Payload map[string]interface{} `json:"payload"`
 }
 
+// ParseToEnvelope decodes numbers as json.Number so snowflake IDs above 2^53 keep their precision.
 func ParseToEnvelope(value []byte, envelope *Envelope) error {
-	if err := json.Unmarshal(value, &envelope); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(envelope); err != nil {
 		log.Printf("ERROR: Failed to deserialize Debezium JSON: %v. Message: %s", err, string(value))
 		return err
 	}
 	return nil
 }
 
+// ParseMessage turns a Kafka record into an envelope. Tombstones (null value) become
+// delete envelopes built from the record key, and deletes whose 'before' image lacks
+// the id are completed from the key as well.
+func ParseMessage(rawKey, value []byte, envelope *Envelope) error {
+	if len(value) == 0 {
+		id, err := ParseKey(rawKey)
+		if err != nil {
+			return fmt.Errorf("tombstone sem chave válida: %w", err)
+		}
+
+		envelope.Payload.Op = "d"
+		envelope.Payload.Before = map[string]interface{}{"id": json.Number(fmt.Sprint(id))}
+		envelope.Tombstone = true
+		return nil
+	}
+
+	if err := ParseToEnvelope(value, envelope); err != nil {
+		return err
+	}
+
+	if envelope.Payload.Op == "d" && envelope.Payload.Before["id"] == nil && len(rawKey) > 0 {
+		id, err := ParseKey(rawKey)
+		if err != nil {
+			return err
+		}
+
+		if envelope.Payload.Before == nil {
+			envelope.Payload.Before = map[string]interface{}{}
+		}
+		envelope.Payload.Before["id"] = json.Number(fmt.Sprint(id))
+	}
+
+	return nil
+}
+
+// ParseKey reads the primary key from a Debezium record key, with or without the schema wrapper.
+func ParseKey(rawKey []byte) (int64, error) {
+	if len(rawKey) == 0 {
+		return 0, fmt.Errorf("chave da mensagem vazia")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawKey))
+	decoder.UseNumber()
+
+	var wrapped key
+	if err := decoder.Decode(&wrapped); err != nil {
+		return 0, fmt.Errorf("chave da mensagem inválida: %w", err)
+	}
+
+	if wrapped.Payload != nil {
+		return parseID(wrapped.Payload["id"])
+	}
+
+	var plain map[string]interface{}
+	decoder = json.NewDecoder(bytes.NewReader(rawKey))
+	decoder.UseNumber()
+	if err := decoder.Decode(&plain); err != nil {
+		return 0, fmt.Errorf("chave da mensagem inválida: %w", err)
+	}
+
+	return parseID(plain["id"])
+}
+
+func parseID(raw interface{}) (int64, error) {
+	switch id := raw.(type) {
+	case json.Number:
+		return id.Int64()
+	case float64:
+		return int64(id), nil
+	case int64:
+		return id, nil
+	default:
+		return 0, fmt.Errorf("ID inválido: esperado número, obteve %T", raw)
+	}
+}
+
 func parseTime(raw interface{}, fieldName string) (time.Time, error) {
 	if raw == nil {
 		return time.Time{}, fmt.Errorf("the field required %s is null", fieldName)
@@ -47,6 +138,69 @@ func parseTime(raw interface{}, fieldName string) (time.Time, error) {
 	return t, nil
 }
 
+func parseOptionalString(raw interface{}, fieldName string) (string, error) {
+	if raw == nil {
+		return "", nil
+	}
+
+	value, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("the field %s is not a string is %T", fieldName, raw)
+	}
+	return value, nil
+}
+
+// parseOptionalInt returns 0 for a missing column so events produced before it existed still project.
+func parseOptionalInt(raw interface{}, fieldName string) (int, error) {
+	if raw == nil {
+		return 0, nil
+	}
+
+	value, err := parseID(raw)
+	if err != nil {
+		return 0, fmt.Errorf("the field %s is not a number is %T", fieldName, raw)
+	}
+	return int(value), nil
+}
+
+// toastedValue reads a column that Postgres may store out of line, taking it from 'before' when the update left it unchanged.
+func toastedValue(envelope Envelope, column string) interface{} {
+	value := envelope.Payload.After[column]
+	if value == unavailableValue {
+		return envelope.Payload.Before[column]
+	}
+	return value
+}
+
+// parseOptionalJSON reads a jsonb column, which Debezium emits as a JSON encoded string.
+func parseOptionalJSON(raw interface{}, fieldName string, value interface{}) error {
+	if raw == nil {
+		return nil
+	}
+
+	encoded, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("the field %s is not a json string is %T", fieldName, raw)
+	}
+
+	if err := json.Unmarshal([]byte(encoded), value); err != nil {
+		return fmt.Errorf("the field %s is not valid json: %w", fieldName, err)
+	}
+	return nil
+}
+
+func parseOptionalBool(raw interface{}, fieldName string) (bool, error) {
+	if raw == nil {
+		return false, nil
+	}
+
+	value, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("the field %s is not a boolean is %T", fieldName, raw)
+	}
+	return value, nil
+}
+
 func parseOptionalTime(raw interface{}, fieldName string) (*time.Time, error) {
 	if raw == nil {
 		return nil, nil
@@ -59,17 +213,25 @@ func parseOptionalTime(raw interface{}, fieldName string) (*time.Time, error) {
 	return &t, nil
 }
 
+// domainOf is the lowercased host of the destination without a leading "www.", which is
+// what the read API matches the domain filter of the link listing against.
+func domainOf(longURL string) string {
+	parsed, err := url.Parse(longURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
+
 func GetLinkFromAfter(envelope Envelope) (models.Link, error) {
 	after := envelope.Payload.After
 	link := models.Link{}
 
-	if rawID, ok := after["id"].(float64); ok {
-		link.ID = int64(rawID)
-	} else if rawID, ok := after["id"].(int64); ok {
-		link.ID = rawID
-	} else {
-		return models.Link{}, fmt.Errorf("ID inválido: esperado float64 ou int64, obteve %T", after["id"])
+	id, err := parseID(after["id"])
+	if err != nil {
+		return models.Link{}, err
 	}
+	link.ID = id
 
 	var ok bool
 	if link.SHORT_CODE, ok = after["short_code"].(string); !ok {
@@ -80,6 +242,13 @@ func GetLinkFromAfter(envelope Envelope) (models.Link, error) {
 		return models.Link{}, fmt.Errorf("long_url inválido: esperado string, obteve %T", after["long_url"])
 	}
 
+	if link.LONG_URL == unavailableValue {
+		if link.LONG_URL, ok = envelope.Payload.Before["long_url"].(string); !ok || link.LONG_URL == unavailableValue {
+			return models.Link{}, fmt.Errorf("long_url indisponível no evento de update e ausente em 'before'")
+		}
+	}
+	link.Domain = domainOf(link.LONG_URL)
+
 	createdAt, err := parseTime(after["created_at"], "created_at")
 	if err != nil {
 		return models.Link{}, err
@@ -92,5 +261,90 @@ func GetLinkFromAfter(envelope Envelope) (models.Link, error) {
 	}
 	link.ExpiresAt = expiresAt
 
+	activatesAt, err := parseOptionalTime(after["activates_at"], "activates_at")
+	if err != nil {
+		return models.Link{}, err
+	}
+	link.ActivatesAt = activatesAt
+
+	redirectType, err := parseOptionalInt(after["redirect_type"], "redirect_type")
+	if err != nil {
+		return models.Link{}, err
+	}
+	link.RedirectType = redirectType
+
+	passwordHash, err := parseOptionalString(after["password_hash"], "password_hash")
+	if err != nil {
+		return models.Link{}, err
+	}
+	link.PasswordHash = passwordHash
+
+	if after["max_clicks"] != nil {
+		maxClicks, err := parseOptionalInt(after["max_clicks"], "max_clicks")
+		if err != nil {
+			return models.Link{}, err
+		}
+		link.MaxClicks = &maxClicks
+	}
+
+	if err := parseOptionalJSON(toastedValue(envelope, "geo_targets"), "geo_targets", &link.GeoTargets); err != nil {
+		return models.Link{}, err
+	}
+
+	if err := parseOptionalJSON(toastedValue(envelope, "device_targets"), "device_targets", &link.DeviceTargets); err != nil {
+		return models.Link{}, err
+	}
+
+	if err := parseOptionalJSON(toastedValue(envelope, "variants"), "variants", &link.Variants); err != nil {
+		return models.Link{}, err
+	}
+
+	link.StickyVariants, err = parseOptionalBool(after["sticky_variants"], "sticky_variants")
+	if err != nil {
+		return models.Link{}, err
+	}
+
+	if after["owner_id"] != nil {
+		ownerID, err := parseID(after["owner_id"])
+		if err != nil {
+			return models.Link{}, fmt.Errorf("owner_id inválido: %w", err)
+		}
+		link.OwnerID = &ownerID
+	}
+
+	link.SourceLSN, link.SourceTsMs = GetSourcePosition(envelope)
+
 	return link, nil
 }
+
+// GetSourcePosition returns the WAL position (lsn) and commit time (ts_ms) of the change, or zero when absent.
+func GetSourcePosition(envelope Envelope) (int64, int64) {
+	source := envelope.Payload.Source
+
+	lsn, err := parseID(source["lsn"])
+	if err != nil {
+		lsn = 0
+	}
+
+	tsMs, err := parseID(source["ts_ms"])
+	if err != nil {
+		tsMs = 0
+	}
+
+	return lsn, tsMs
+}
+
+// GetShortCodeFromBefore returns the deleted short code, or "" when the before image does not carry it.
+func GetShortCodeFromBefore(envelope Envelope) string {
+	code, _ := envelope.Payload.Before["short_code"].(string)
+	return code
+}
+
+func GetLinkIDFromBefore(envelope Envelope) (int64, error) {
+	before := envelope.Payload.Before
+	if before == nil {
+		return 0, fmt.Errorf("'before' ausente no evento de delete")
+	}
+
+	return parseID(before["id"])
+}
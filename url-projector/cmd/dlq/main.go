@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"linkfast/url-projector/dlq"
+	"linkfast/url-projector/utils/envs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const usage = `Usage: dlq <inspect|replay> [flags]
+
+  inspect   print the messages stored in the dead-letter topic
+  replay    publish the dead-letter messages back into the main topic
+
+Flags:
+`
+
+type options struct {
+	brokers  string
+	topic    string
+	dlqTopic string
+	groupID  string
+	limit    int
+	idle     time.Duration
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	kafkaTopic := envs.GetEnvWithFallback("KAFKA_TOPIC", "")
+
+	opts := options{}
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.brokers, "brokers", envs.GetEnvWithFallback("KAFKA_BROKERS", "localhost:9092"), "Kafka bootstrap servers")
+	flags.StringVar(&opts.topic, "topic", kafkaTopic, "main topic that receives replayed messages")
+	flags.StringVar(&opts.dlqTopic, "dlq-topic", envs.GetEnvWithFallback("KAFKA_DLQ_TOPIC", kafkaTopic+".dlq"), "dead-letter topic")
+	flags.StringVar(&opts.groupID, "group", "link_fast_dlq_replayer", "consumer group used by replay to remember what was already replayed")
+	flags.IntVar(&opts.limit, "limit", 0, "maximum number of messages to process (0 = all)")
+	flags.DurationVar(&opts.idle, "idle", 5*time.Second, "stop after this long without new messages")
+	flags.Parse(os.Args[2:])
+
+	var err error
+	switch command {
+	case "inspect":
+		err = inspect(opts)
+	case "replay":
+		err = replay(opts)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("dlq %s failed: %v", command, err)
+	}
+}
+
+func inspect(opts options) error {
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  opts.brokers,
+		"group.id":           fmt.Sprintf("link_fast_dlq_inspector_%d", time.Now().UnixNano()),
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	if err := consumer.Subscribe(opts.dlqTopic, nil); err != nil {
+		return err
+	}
+
+	return readUntilIdle(consumer, opts, func(msg *kafka.Message) error {
+		fmt.Printf("offset=%s key=%s\n", msg.TopicPartition.Offset, string(msg.Key))
+		fmt.Printf("  source:    %s[%s]@%s\n",
+			dlq.HeaderValue(msg.Headers, dlq.HeaderSourceTopic),
+			dlq.HeaderValue(msg.Headers, dlq.HeaderSourcePartition),
+			dlq.HeaderValue(msg.Headers, dlq.HeaderSourceOffset))
+		fmt.Printf("  failed at: %s\n", dlq.HeaderValue(msg.Headers, dlq.HeaderFailedAt))
+		fmt.Printf("  error:     %s\n", dlq.HeaderValue(msg.Headers, dlq.HeaderError))
+		fmt.Printf("  payload:   %s\n\n", string(msg.Value))
+		return nil
+	})
+}
+
+func replay(opts options) error {
+	if opts.topic == "" {
+		return fmt.Errorf("the main topic is required (-topic or KAFKA_TOPIC)")
+	}
+
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  opts.brokers,
+		"group.id":           opts.groupID,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":  opts.brokers,
+		"acks":               "all",
+		"enable.idempotence": true,
+	})
+	if err != nil {
+		return err
+	}
+	defer producer.Close()
+
+	if err := consumer.Subscribe(opts.dlqTopic, nil); err != nil {
+		return err
+	}
+
+	delivery := make(chan kafka.Event, 1)
+	defer close(delivery)
+
+	replayed := 0
+	err = readUntilIdle(consumer, opts, func(msg *kafka.Message) error {
+		err := producer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &opts.topic, Partition: kafka.PartitionAny},
+			Key:            msg.Key,
+			Value:          msg.Value,
+			Headers:        dlq.StripHeaders(msg.Headers),
+		}, delivery)
+		if err != nil {
+			return err
+		}
+
+		if err := dlq.DeliveryError(<-delivery); err != nil {
+			return err
+		}
+
+		if _, err := consumer.CommitMessage(msg); err != nil {
+			return err
+		}
+
+		replayed++
+		log.Printf("Replayed dead-letter offset %s into %s", msg.TopicPartition.Offset, opts.topic)
+		return nil
+	})
+
+	log.Printf("%d message(s) replayed into %s", replayed, opts.topic)
+	return err
+}
+
+func readUntilIdle(consumer *kafka.Consumer, opts options, handle func(msg *kafka.Message) error) error {
+	processed := 0
+	lastMessage := time.Now()
+
+	for opts.limit == 0 || processed < opts.limit {
+		msg, err := consumer.ReadMessage(time.Second)
+		if err != nil {
+			if kafkaErr, ok := err.(kafka.Error); ok && kafkaErr.Code() == kafka.ErrTimedOut {
+				if time.Since(lastMessage) >= opts.idle {
+					return nil
+				}
+				continue
+			}
+			return err
+		}
+
+		if err := handle(msg); err != nil {
+			return err
+		}
+
+		processed++
+		lastMessage = time.Now()
+	}
+
+	return nil
+}
@@ -2,27 +2,32 @@ package consumer
 
 import (
 	"context"
+	"fmt"
 	"linkfast/url-projector/cdc"
+	configs "linkfast/url-projector/config"
+	"linkfast/url-projector/dlq"
+	"linkfast/url-projector/repositories"
 	"linkfast/url-projector/services"
+	"linkfast/url-projector/utils/consts"
 	"log"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
-var consumerGroupID = "link_fast_group"
-
 const retryDelay = 5 * time.Second
 
-func LinkConsumer(brokers, topic string, service services.LinkService) {
+func LinkConsumer(cfg configs.KafkaConfig, service services.LinkService, deadLetters dlq.Publisher) {
+	brokers, topic := cfg.Brokers, cfg.Topic
+
 	for {
 		log.Printf("Tentando conectar ao Kafka em %s...", brokers)
 
 		consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
 			"bootstrap.servers":  brokers,
-			"group.id":           consumerGroupID,
+			"group.id":           cfg.GroupID,
 			"auto.offset.reset":  "earliest",
-			"enable.auto.commit": true,
+			"enable.auto.commit": false,
 			"socket.timeout.ms":  3000,
 		})
 
@@ -43,7 +48,7 @@ func LinkConsumer(brokers, topic string, service services.LinkService) {
 
 		log.Printf("Inscrição no tópico '%s' bem-sucedida. Começando a consumir mensagens.", topic)
 
-		consumeLoop(consumer, service)
+		consumeLoop(consumer, cfg, service, deadLetters)
 
 		log.Printf("Loop de consumo encerrado. Tentando reconectar ao Kafka em %s...", retryDelay)
 		consumer.Close()
@@ -51,32 +56,154 @@ func LinkConsumer(brokers, topic string, service services.LinkService) {
 	}
 }
 
-func consumeLoop(consumer *kafka.Consumer, service services.LinkService) {
+func consumeLoop(consumer *kafka.Consumer, cfg configs.KafkaConfig, service services.LinkService, deadLetters dlq.Publisher) {
+	batch := make([]*kafka.Message, 0, cfg.BatchSize)
+	var batchStarted time.Time
+
 	for {
-		msg, err := consumer.ReadMessage(time.Second)
+		timeout := time.Second
+		if len(batch) > 0 {
+			timeout = max(time.Until(batchStarted.Add(cfg.BatchWindow)), time.Millisecond)
+		}
 
-		if err == nil {
-			var envelope cdc.Envelope
+		msg, err := consumer.ReadMessage(timeout)
 
-			if err := cdc.ParseToEnvelope(msg.Value, &envelope); err != nil {
-				log.Printf("Erro ao parsear mensagem: %s", err.Error())
-				continue
+		if err == nil {
+			if len(batch) == 0 {
+				batchStarted = time.Now()
 			}
-
-			ctx := context.Background()
-			service.ApplyLogic(ctx, envelope)
+			batch = append(batch, msg)
 
 		} else if kafkaErr, ok := err.(kafka.Error); ok {
-			if kafkaErr.Code() == kafka.ErrTimedOut {
-				continue
-			} else if kafkaErr.Code() == kafka.ErrAllBrokersDown || kafkaErr.IsFatal() {
+			if kafkaErr.Code() == kafka.ErrAllBrokersDown || kafkaErr.IsFatal() {
 				log.Printf("Erro fatal do consumer, re-tentando conexão: %v\n", err)
 				return
-			} else {
+			} else if kafkaErr.Code() != kafka.ErrTimedOut {
 				log.Printf("Erro do consumer: %v\n", err)
 			}
 		} else {
 			log.Printf("Erro inesperado durante a leitura: %v\n", err)
 		}
+
+		if len(batch) == 0 || (len(batch) < cfg.BatchSize && time.Since(batchStarted) < cfg.BatchWindow) {
+			continue
+		}
+
+		if err := ProcessBatch(batch, cfg, service, deadLetters); err != nil {
+			log.Printf("ERROR: Batch of %d messages could not be projected nor dead-lettered, re-consuming from last commit: %v", len(batch), err)
+			return
+		}
+
+		if err := commitBatch(consumer, batch); err != nil {
+			log.Printf("Erro ao commitar os offsets do lote: %v", err)
+		}
+
+		batch = batch[:0]
+	}
+}
+
+// ProcessBatch projects a batch of CDC messages, dead-lettering the ones that cannot be parsed
+// or applied. It only fails when a message could not be dead-lettered either.
+func ProcessBatch(batch []*kafka.Message, cfg configs.KafkaConfig, service services.LinkService, deadLetters dlq.Publisher) error {
+	writes := make([]repositories.LinkWrite, 0, len(batch))
+	sources := make([]*kafka.Message, 0, len(batch))
+
+	for _, msg := range batch {
+		var envelope cdc.Envelope
+
+		if err := cdc.ParseMessage(msg.Key, msg.Value, &envelope); err != nil {
+			log.Printf("Erro ao parsear mensagem %v: %s", msg.TopicPartition, err.Error())
+			if err := deadLetter(msg, cfg, deadLetters, fmt.Errorf("%w: %v", consts.ErrInvalidEnvelope, err)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		write, err := service.ToWrite(envelope)
+		if err != nil {
+			if err := deadLetter(msg, cfg, deadLetters, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if write != nil {
+			writes = append(writes, *write)
+			sources = append(sources, msg)
+		}
+	}
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	err := retryWithBackoff(cfg, func() error {
+		return service.ApplyBatch(context.Background(), writes)
+	})
+
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("ERROR: Giving up on batch of %d writes after %d retries, applying them one by one: %v", len(writes), cfg.MaxRetries, err)
+	return applyOneByOne(writes, sources, cfg, service, deadLetters)
+}
+
+// applyOneByOne isolates the writes that fail a batch, so only their source messages are dead-lettered.
+func applyOneByOne(writes []repositories.LinkWrite, sources []*kafka.Message, cfg configs.KafkaConfig, service services.LinkService, deadLetters dlq.Publisher) error {
+	for i, write := range writes {
+		err := retryWithBackoff(cfg, func() error {
+			return service.ApplyBatch(context.Background(), []repositories.LinkWrite{write})
+		})
+
+		if err == nil {
+			continue
+		}
+
+		log.Printf("ERROR: Giving up on write of link %d after %d retries: %v", write.ID, cfg.MaxRetries, err)
+		if err := deadLetter(sources[i], cfg, deadLetters, err); err != nil {
+			return err
+		}
 	}
+
+	return nil
+}
+
+// commitBatch commits, for every partition in the batch, the offset right after its last message.
+func commitBatch(consumer *kafka.Consumer, batch []*kafka.Message) error {
+	type partitionKey struct {
+		topic     string
+		partition int32
+	}
+
+	next := map[partitionKey]kafka.TopicPartition{}
+	for _, msg := range batch {
+		tp := msg.TopicPartition
+		key := partitionKey{topic: *tp.Topic, partition: tp.Partition}
+
+		if current, ok := next[key]; !ok || tp.Offset+1 > current.Offset {
+			next[key] = kafka.TopicPartition{Topic: tp.Topic, Partition: tp.Partition, Offset: tp.Offset + 1}
+		}
+	}
+
+	offsets := make([]kafka.TopicPartition, 0, len(next))
+	for _, tp := range next {
+		offsets = append(offsets, tp)
+	}
+
+	_, err := consumer.CommitOffsets(offsets)
+	return err
+}
+
+func deadLetter(msg *kafka.Message, cfg configs.KafkaConfig, deadLetters dlq.Publisher, reason error) error {
+	err := retryWithBackoff(cfg, func() error {
+		return deadLetters.Publish(msg, reason)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to publish to dead-letter topic %s: %w", cfg.DLQTopic, err)
+	}
+
+	log.Printf("WARN: Message %v sent to dead-letter topic %s: %v", msg.TopicPartition, cfg.DLQTopic, reason)
+	return nil
 }
@@ -0,0 +1,20 @@
+package consumer
+
+import (
+	"errors"
+	"linkfast/shared/retry"
+	configs "linkfast/url-projector/config"
+	"linkfast/url-projector/utils/consts"
+)
+
+// retryWithBackoff retries fn with the Kafka retry settings. Invalid envelopes are not retried.
+func retryWithBackoff(cfg configs.KafkaConfig, fn func() error) error {
+	return retry.Do(retry.Policy{
+		MaxRetries: cfg.MaxRetries,
+		Backoff:    cfg.RetryBackoff,
+		MaxBackoff: cfg.MaxRetryBackoff,
+		Permanent: func(err error) bool {
+			return errors.Is(err, consts.ErrInvalidEnvelope)
+		},
+	}, fn)
+}
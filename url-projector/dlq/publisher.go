@@ -0,0 +1,113 @@
+package dlq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const (
+	headerPrefix          = "dlq."
+	HeaderError           = "dlq.error"
+	HeaderSourceTopic     = "dlq.source.topic"
+	HeaderSourcePartition = "dlq.source.partition"
+	HeaderSourceOffset    = "dlq.source.offset"
+	HeaderFailedAt        = "dlq.failed_at"
+)
+
+type Publisher interface {
+	Publish(msg *kafka.Message, reason error) error
+	Close()
+}
+
+type kafkaPublisher struct {
+	producer *kafka.Producer
+	topic    string
+}
+
+func NewPublisher(brokers, topic string) (Publisher, error) {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":  brokers,
+		"acks":               "all",
+		"enable.idempotence": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafkaPublisher{
+		producer: producer,
+		topic:    topic,
+	}, nil
+}
+
+func (p *kafkaPublisher) Publish(msg *kafka.Message, reason error) error {
+	delivery := make(chan kafka.Event, 1)
+	defer close(delivery)
+
+	headers := append(StripHeaders(msg.Headers),
+		kafka.Header{Key: HeaderError, Value: []byte(reason.Error())},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+
+	if source := msg.TopicPartition; source.Topic != nil {
+		headers = append(headers,
+			kafka.Header{Key: HeaderSourceTopic, Value: []byte(*source.Topic)},
+			kafka.Header{Key: HeaderSourcePartition, Value: []byte(strconv.Itoa(int(source.Partition)))},
+			kafka.Header{Key: HeaderSourceOffset, Value: []byte(source.Offset.String())},
+		)
+	}
+
+	err := p.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &p.topic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}, delivery)
+	if err != nil {
+		return err
+	}
+
+	return DeliveryError(<-delivery)
+}
+
+// DeliveryError returns the failure carried by a delivery report, which is either the produced
+// message or a client-level kafka.Error.
+func DeliveryError(event kafka.Event) error {
+	switch report := event.(type) {
+	case *kafka.Message:
+		return report.TopicPartition.Error
+	case kafka.Error:
+		return report
+	default:
+		return fmt.Errorf("unexpected delivery report: %v", event)
+	}
+}
+
+func (p *kafkaPublisher) Close() {
+	p.producer.Flush(5000)
+	p.producer.Close()
+}
+
+// StripHeaders drops the headers added by a previous dead-lettering so a replayed message looks like the original.
+func StripHeaders(headers []kafka.Header) []kafka.Header {
+	original := []kafka.Header{}
+	for _, header := range headers {
+		if !strings.HasPrefix(header.Key, headerPrefix) {
+			original = append(original, header)
+		}
+	}
+	return original
+}
+
+func HeaderValue(headers []kafka.Header, key string) string {
+	for _, header := range headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
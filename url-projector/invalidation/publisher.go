@@ -0,0 +1,95 @@
+package invalidation
+
+import (
+	"encoding/json"
+	"linkfast/url-projector/repositories"
+	"log"
+	"strconv"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const (
+	OpUpsert = "upsert"
+	OpDelete = "delete"
+)
+
+type Event struct {
+	Op        string `json:"op"`
+	ID        int64  `json:"id"`
+	ShortCode string `json:"short_code"`
+}
+
+type Publisher interface {
+	Publish(writes []repositories.LinkWrite)
+	Close()
+}
+
+type kafkaPublisher struct {
+	producer *kafka.Producer
+	topic    string
+}
+
+// NewPublisher is best effort: read-api caches also expire by TTL, so a lost invalidation
+// only delays an update instead of making it invisible.
+func NewPublisher(brokers, topic string) (Publisher, error) {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": brokers,
+		"linger.ms":         10,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for e := range producer.Events() {
+			if msg, ok := e.(*kafka.Message); ok && msg.TopicPartition.Error != nil {
+				log.Printf("WARN: Failed to publish cache invalidation: %v", msg.TopicPartition.Error)
+			}
+		}
+	}()
+
+	return &kafkaPublisher{
+		producer: producer,
+		topic:    topic,
+	}, nil
+}
+
+func (p *kafkaPublisher) Publish(writes []repositories.LinkWrite) {
+	for _, write := range writes {
+		event := Event{Op: OpUpsert, ID: write.ID, ShortCode: write.ShortCode}
+		if write.Op == repositories.WriteDelete {
+			event.Op = OpDelete
+		}
+
+		value, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("WARN: Failed to encode cache invalidation for link %d: %v", write.ID, err)
+			continue
+		}
+
+		err = p.producer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &p.topic, Partition: kafka.PartitionAny},
+			Key:            []byte(strconv.FormatInt(write.ID, 10)),
+			Value:          value,
+		}, nil)
+		if err != nil {
+			log.Printf("WARN: Failed to enqueue cache invalidation for link %d: %v", write.ID, err)
+		}
+	}
+}
+
+func (p *kafkaPublisher) Close() {
+	p.producer.Flush(5000)
+	p.producer.Close()
+}
+
+type noopPublisher struct{}
+
+func NewNoopPublisher() Publisher {
+	return noopPublisher{}
+}
+
+func (noopPublisher) Publish(writes []repositories.LinkWrite) {}
+
+func (noopPublisher) Close() {}
@@ -4,6 +4,8 @@ import (
 	"context"
 	configs "linkfast/url-projector/config"
 	"linkfast/url-projector/consumer"
+	"linkfast/url-projector/dlq"
+	"linkfast/url-projector/invalidation"
 	"linkfast/url-projector/repositories"
 	"linkfast/url-projector/services"
 	"linkfast/url-projector/utils/envs"
@@ -21,6 +23,16 @@ func main() {
 	kafkaTopic := envs.GetEnvWithFallback("KAFKA_TOPIC", "")
 	mongoURI := envs.GetEnvWithFallback("MONGO_URI", "")
 	mongoDBName := envs.GetEnvWithFallback("MONGO_DB_NAME", "")
+	expiredRetention := envs.GetEnvAsIntWithFallback("EXPIRED_LINK_RETENTION_SECONDS", 86400)
+	tombstoneRetention := envs.GetEnvAsIntWithFallback("TOMBSTONE_RETENTION_SECONDS", 604800)
+	kafkaGroupID := envs.GetEnvWithFallback("KAFKA_GROUP_ID", "link_fast_group")
+	kafkaDLQTopic := envs.GetEnvWithFallback("KAFKA_DLQ_TOPIC", kafkaTopic+".dlq")
+	batchSize := envs.GetEnvAsIntWithFallback("PROJECTOR_BATCH_SIZE", 500)
+	batchWindowMs := envs.GetEnvAsIntWithFallback("PROJECTOR_BATCH_WINDOW_MS", 200)
+	maxRetries := envs.GetEnvAsIntWithFallback("PROJECTOR_MAX_RETRIES", 5)
+	retryBackoffMs := envs.GetEnvAsIntWithFallback("PROJECTOR_RETRY_BACKOFF_MS", 500)
+	maxRetryBackoffMs := envs.GetEnvAsIntWithFallback("PROJECTOR_RETRY_MAX_BACKOFF_MS", 10000)
+	invalidationTopic := envs.GetEnvWithFallback("LINK_INVALIDATION_TOPIC", "link_fast.link_invalidations")
 
 	required := map[string]string{
 		"KAFKA_BROKERS": kafkaBrokers,
@@ -54,8 +66,36 @@ func main() {
 
 	linkRepo := repositories.NewLinkRepository(mongoDB)
 
-	linkService := services.NewLinkService(linkRepo)
+	indexCtx, cancelIndex := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := linkRepo.EnsureIndexes(indexCtx, time.Duration(expiredRetention)*time.Second, time.Duration(tombstoneRetention)*time.Second); err != nil {
+		log.Printf("WARNING: Failed to ensure indexes on links collection: %v", err)
+	}
+	cancelIndex()
+
+	invalidations, err := invalidation.NewPublisher(kafkaBrokers, invalidationTopic)
+	if err != nil {
+		log.Fatalf("Falha crítica ao criar o producer de invalidação de cache: %v", err)
+	}
+	defer invalidations.Close()
+
+	linkService := services.NewLinkService(linkRepo, invalidations)
+
+	deadLetters, err := dlq.NewPublisher(kafkaBrokers, kafkaDLQTopic)
+	if err != nil {
+		log.Fatalf("Falha crítica ao criar o producer da dead-letter: %v", err)
+	}
+	defer deadLetters.Close()
 
 	log.Println("Reading topics!")
-	consumer.LinkConsumer(kafkaBrokers, kafkaTopic, linkService)
+	consumer.LinkConsumer(configs.KafkaConfig{
+		Brokers:         kafkaBrokers,
+		Topic:           kafkaTopic,
+		GroupID:         kafkaGroupID,
+		DLQTopic:        kafkaDLQTopic,
+		BatchSize:       max(batchSize, 1),
+		BatchWindow:     time.Duration(batchWindowMs) * time.Millisecond,
+		MaxRetries:      maxRetries,
+		RetryBackoff:    time.Duration(retryBackoffMs) * time.Millisecond,
+		MaxRetryBackoff: time.Duration(maxRetryBackoffMs) * time.Millisecond,
+	}, linkService, deadLetters)
 }
@@ -3,9 +3,27 @@ package models
 import "time"
 
 type Link struct {
-	ID         int64      `json:"id" bson:"_id"`
-	SHORT_CODE string     `json:"short_code" bson:"short_code"`
-	LONG_URL   string     `json:"long_url" bson:"long_url"`
-	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
-	ExpiresAt  *time.Time `json:"expires_at" bson:"expires_at"`
+	ID             int64             `json:"id" bson:"_id"`
+	SHORT_CODE     string            `json:"short_code" bson:"short_code"`
+	LONG_URL       string            `json:"long_url" bson:"long_url"`
+	Domain         string            `json:"domain" bson:"domain,omitempty"`
+	CreatedAt      time.Time         `json:"created_at" bson:"created_at"`
+	ExpiresAt      *time.Time        `json:"expires_at" bson:"expires_at"`
+	ActivatesAt    *time.Time        `json:"activates_at" bson:"activates_at,omitempty"`
+	RedirectType   int               `json:"redirect_type" bson:"redirect_type"`
+	PasswordHash   string            `json:"-" bson:"password_hash,omitempty"`
+	MaxClicks      *int              `json:"max_clicks" bson:"max_clicks,omitempty"`
+	GeoTargets     map[string]string `json:"geo_targets" bson:"geo_targets,omitempty"`
+	DeviceTargets  map[string]string `json:"device_targets" bson:"device_targets,omitempty"`
+	Variants       []LinkVariant     `json:"variants" bson:"variants,omitempty"`
+	StickyVariants bool              `json:"sticky_variants" bson:"sticky_variants,omitempty"`
+	OwnerID        *int64            `json:"owner_id" bson:"owner_id,omitempty"`
+	SourceLSN      int64             `json:"source_lsn" bson:"source_lsn"`
+	SourceTsMs     int64             `json:"source_ts_ms" bson:"source_ts_ms"`
+}
+
+type LinkVariant struct {
+	Name   string `json:"name" bson:"name"`
+	URL    string `json:"url" bson:"url"`
+	Weight int    `json:"weight" bson:"weight"`
 }
@@ -2,105 +2,203 @@ package repositories
 
 import (
 	"context"
-	"errors"
 	models "linkfast/url-projector/model"
-	"linkfast/url-projector/utils/consts"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+type WriteOp int
+
+const (
+	WriteUpsert WriteOp = iota
+	WriteDelete
+)
+
+type LinkWrite struct {
+	Op        WriteOp
+	ID        int64
+	ShortCode string
+	LSN       int64
+	Link      models.Link
+}
+
+type BulkResult struct {
+	Upserted int64
+	Modified int64
+	Deleted  int64
+	Stale    int64
+}
+
 type LinkRepository interface {
-	GetByCode(ctx context.Context, code string) (models.Link, error)
-	GetById(ctx context.Context, id int64) (models.Link, error)
-	Delete(ctx context.Context, id int64) error
-	Create(ctx context.Context, link models.Link) (models.Link, error)
-	Upsert(ctx context.Context, cdcLink *models.Link) (*models.Link, error)
+	EnsureIndexes(ctx context.Context, expiredRetention, tombstoneRetention time.Duration) error
+	BulkWrite(ctx context.Context, writes []LinkWrite) (BulkResult, error)
 }
 
 type linkRepository struct {
 	collection *mongo.Collection
+	tombstones *mongo.Collection
 }
 
 func NewLinkRepository(db *mongo.Database) LinkRepository {
 	return &linkRepository{
 		collection: db.Collection("links"),
+		tombstones: db.Collection("link_tombstones"),
 	}
 }
 
-func (l *linkRepository) Upsert(ctx context.Context, cdcLink *models.Link) (*models.Link, error) {
-
-	filter := bson.M{"_id": cdcLink.ID}
-	mongoLink := models.Link{
-		ID:         cdcLink.ID,
-		SHORT_CODE: cdcLink.SHORT_CODE,
-		LONG_URL:   cdcLink.LONG_URL,
-		CreatedAt:  cdcLink.CreatedAt,
-		ExpiresAt:  cdcLink.ExpiresAt,
+func (l *linkRepository) EnsureIndexes(ctx context.Context, expiredRetention, tombstoneRetention time.Duration) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "short_code", Value: 1}},
+			Options: options.Index().SetName("short_code_unique").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().
+				SetName("expires_at_ttl").
+				SetExpireAfterSeconds(int32(expiredRetention.Seconds())),
+		},
+		{
+			Keys:    bson.D{{Key: "domain", Value: 1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("domain_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("created_at_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("owner_id_id"),
+		},
 	}
 
-	opts := options.Replace().SetUpsert(true)
-
-	_, err := l.collection.ReplaceOne(ctx, filter, mongoLink, opts)
-	if err != nil {
-		return nil, consts.ErrInternal
+	if _, err := l.collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return err
 	}
 
-	return &mongoLink, nil
+	_, err := l.tombstones.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "deleted_at", Value: 1}},
+		Options: options.Index().
+			SetName("deleted_at_ttl").
+			SetExpireAfterSeconds(int32(tombstoneRetention.Seconds())),
+	})
+
+	return err
 }
 
-func (l *linkRepository) GetByCode(ctx context.Context, code string) (models.Link, error) {
-	var link models.Link
+// BulkWrite applies upserts only when the event is newer than the stored source_lsn and
+// records deletes as tombstones, so replayed or out-of-order events cannot resurrect or
+// roll back a document. Skipped events are reported in BulkResult.Stale.
+func (l *linkRepository) BulkWrite(ctx context.Context, writes []LinkWrite) (BulkResult, error) {
+	if len(writes) == 0 {
+		return BulkResult{}, nil
+	}
 
-	filter := bson.M{"short_code": code}
+	deleted, err := l.deletedIDs(ctx, writes)
+	if err != nil {
+		return BulkResult{}, err
+	}
 
-	if err := l.collection.FindOne(ctx, filter).Decode(&link); err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return link, consts.ErrRecordNotFound
+	var stale int64
+	operations := make([]mongo.WriteModel, 0, len(writes))
+	tombstones := make([]mongo.WriteModel, 0)
+
+	for _, write := range writes {
+		switch write.Op {
+		case WriteUpsert:
+			if deleted[write.ID] {
+				stale++
+				continue
+			}
+
+			operations = append(operations, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": write.ID}).
+				SetUpdate(newerThanStored(write.LSN, write.Link)).
+				SetUpsert(true))
+		case WriteDelete:
+			operations = append(operations, mongo.NewDeleteOneModel().
+				SetFilter(bson.M{"_id": write.ID}))
+			tombstones = append(tombstones, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": write.ID}).
+				SetUpdate(bson.M{
+					"$max":         bson.M{"source_lsn": write.LSN},
+					"$setOnInsert": bson.M{"deleted_at": time.Now()},
+				}).
+				SetUpsert(true))
 		}
+	}
 
-		return link, consts.ErrInternal
+	if len(tombstones) > 0 {
+		if _, err := l.tombstones.BulkWrite(ctx, tombstones, options.BulkWrite().SetOrdered(true)); err != nil {
+			return BulkResult{}, err
+		}
 	}
 
-	return link, nil
-}
+	if len(operations) == 0 {
+		return BulkResult{Stale: stale}, nil
+	}
 
-func (l *linkRepository) GetById(ctx context.Context, id int64) (models.Link, error) {
-	var link models.Link
+	result, err := l.collection.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return BulkResult{}, err
+	}
 
-	filter := bson.M{"_id": id}
+	return BulkResult{
+		Upserted: result.UpsertedCount,
+		Modified: result.ModifiedCount,
+		Deleted:  result.DeletedCount,
+		Stale:    stale + result.MatchedCount - result.ModifiedCount,
+	}, nil
+}
 
-	if err := l.collection.FindOne(ctx, filter).Decode(&link); err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return link, consts.ErrRecordNotFound
+func (l *linkRepository) deletedIDs(ctx context.Context, writes []LinkWrite) (map[int64]bool, error) {
+	ids := []int64{}
+	for _, write := range writes {
+		if write.Op == WriteUpsert {
+			ids = append(ids, write.ID)
 		}
-
-		return link, consts.ErrInternal
 	}
 
-	return link, nil
-}
-
-func (l *linkRepository) Delete(ctx context.Context, id int64) error {
-	filter := bson.M{"_id": id}
-	result, err := l.collection.DeleteOne(ctx, filter)
+	deleted := map[int64]bool{}
+	if len(ids) == 0 {
+		return deleted, nil
+	}
 
+	cursor, err := l.tombstones.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"_id": 1}))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	if result.DeletedCount == 0 {
-		return consts.ErrRecordNotFound
+	for cursor.Next(ctx) {
+		var tombstone struct {
+			ID int64 `bson:"_id"`
+		}
+		if err := cursor.Decode(&tombstone); err != nil {
+			return nil, err
+		}
+		deleted[tombstone.ID] = true
 	}
 
-	return nil
+	return deleted, cursor.Err()
 }
 
-func (l *linkRepository) Create(ctx context.Context, link models.Link) (models.Link, error) {
-	if _, err := l.collection.InsertOne(ctx, link); err != nil {
-		return link, consts.ErrInternal
+// newerThanStored replaces the document only if its stored source_lsn is older than lsn,
+// keeping click_count, which is owned by read-api and not part of the write model.
+func newerThanStored(lsn int64, link models.Link) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$replaceWith", Value: bson.M{
+			"$cond": bson.M{
+				"if": bson.M{"$lt": bson.A{bson.M{"$ifNull": bson.A{"$source_lsn", -1}}, lsn}},
+				"then": bson.M{"$mergeObjects": bson.A{
+					bson.M{"$literal": link},
+					bson.M{"click_count": "$click_count"},
+				}},
+				"else": "$$ROOT",
+			},
+		}}},
 	}
-
-	return link, nil
 }
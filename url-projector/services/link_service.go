@@ -2,73 +2,115 @@ package services
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"linkfast/url-projector/cdc"
+	"linkfast/url-projector/invalidation"
 	"linkfast/url-projector/repositories"
 	"linkfast/url-projector/utils/consts"
 	"log"
+	"slices"
+	"sync/atomic"
 )
 
 type LinkService interface {
-	ApplyLogic(ctx context.Context, envelope cdc.Envelope)
+	ApplyLogic(ctx context.Context, envelope cdc.Envelope) error
+	ToWrite(envelope cdc.Envelope) (*repositories.LinkWrite, error)
+	ApplyBatch(ctx context.Context, writes []repositories.LinkWrite) error
+	StaleEvents() int64
 }
 
 type linkService struct {
-	repo repositories.LinkRepository
+	repo          repositories.LinkRepository
+	invalidations invalidation.Publisher
+	staleEvents   atomic.Int64
 }
 
-func NewLinkService(repo repositories.LinkRepository) LinkService {
+func NewLinkService(repo repositories.LinkRepository, invalidations invalidation.Publisher) LinkService {
 	return &linkService{
-		repo: repo,
+		repo:          repo,
+		invalidations: invalidations,
 	}
 }
 
-func (l *linkService) ApplyLogic(ctx context.Context, envelope cdc.Envelope) {
+func (l *linkService) ApplyLogic(ctx context.Context, envelope cdc.Envelope) error {
+	write, err := l.ToWrite(envelope)
+	if err != nil || write == nil {
+		return err
+	}
+
+	return l.ApplyBatch(ctx, []repositories.LinkWrite{*write})
+}
+
+// ToWrite maps an envelope to the write it produces on the read model, or nil when the op is not projected.
+func (l *linkService) ToWrite(envelope cdc.Envelope) (*repositories.LinkWrite, error) {
 	op := envelope.Payload.Op
 
 	switch op {
 	case "c", "u", "r":
-		l.Upsert(ctx, envelope)
+		link, err := cdc.GetLinkFromAfter(envelope)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", consts.ErrInvalidEnvelope, err)
+		}
+
+		return &repositories.LinkWrite{Op: repositories.WriteUpsert, ID: link.ID, ShortCode: link.SHORT_CODE, LSN: link.SourceLSN, Link: link}, nil
 
 	case "d":
-		if idFloat, ok := envelope.Payload.Before["id"].(int64); ok {
-			l.Delete(ctx, idFloat)
-		} else {
-			log.Printf("ERROR: Delete operation received, but 'id' not found in 'Before' payload or is not numeric. Op: %s", op)
+		id, err := cdc.GetLinkIDFromBefore(envelope)
+		if err != nil {
+			return nil, fmt.Errorf("%w: delete without readable id: %v", consts.ErrInvalidEnvelope, err)
 		}
 
+		lsn, _ := cdc.GetSourcePosition(envelope)
+		return &repositories.LinkWrite{Op: repositories.WriteDelete, ID: id, ShortCode: cdc.GetShortCodeFromBefore(envelope), LSN: lsn}, nil
+
 	default:
 		log.Printf("INFO: No action taken! Op received: %s", op)
+		return nil, nil
 	}
 }
 
-func (l *linkService) Upsert(ctx context.Context, envelope cdc.Envelope) {
-	link, err_after := cdc.GetLinkFromAfter(envelope)
+func (l *linkService) ApplyBatch(ctx context.Context, writes []repositories.LinkWrite) error {
+	collapsed := collapse(writes)
 
-	if err_after != nil {
-		log.Fatalf("Error the get after of envelope! Details error: %v", err_after)
+	result, err := l.repo.BulkWrite(ctx, collapsed)
+	if err != nil {
+		log.Printf("ERROR: Failed to apply batch of %d writes: %v", len(collapsed), err)
+		return err
 	}
 
-	if _, err := l.repo.Upsert(ctx, &link); err != nil {
-		log.Printf("ERROR: Failed to upsert document [ID: %d, Short Code: %s]: %v", link.ID, link.SHORT_CODE, err)
-		return
-	}
+	l.invalidations.Publish(collapsed)
+	total := l.staleEvents.Add(result.Stale)
 
-	log.Printf("SUCCESS: Document Upserted (Op: %s) [ID: %d, Short Code: %s]", envelope.Payload.Op, link.ID, link.SHORT_CODE)
+	log.Printf("SUCCESS: Batch applied: %d events collapsed into %d writes (upserted %d, modified %d, deleted %d, stale %d, stale total %d)",
+		len(writes), len(collapsed), result.Upserted, result.Modified, result.Deleted, result.Stale, total)
+	return nil
 }
 
-func (l *linkService) Delete(ctx context.Context, id int64) {
-	err := l.repo.Delete(ctx, id)
+func (l *linkService) StaleEvents() int64 {
+	return l.staleEvents.Load()
+}
 
-	if err != nil {
-		if errors.Is(err, consts.ErrRecordNotFound) {
-			log.Printf("INFO: Document not found [ID: %d]. Already deleted.", id)
-			return
+// collapse keeps only the last write of each id, ordered by when that last write happened.
+func collapse(writes []repositories.LinkWrite) []repositories.LinkWrite {
+	seen := make(map[int64]int, len(writes))
+	collapsed := make([]repositories.LinkWrite, 0, len(writes))
+
+	for i := len(writes) - 1; i >= 0; i-- {
+		if index, ok := seen[writes[i].ID]; ok {
+			// A tombstone carries neither the short code nor the LSN of the delete it follows.
+			if kept := &collapsed[index]; kept.Op == repositories.WriteDelete {
+				if kept.ShortCode == "" {
+					kept.ShortCode = writes[i].ShortCode
+				}
+				kept.LSN = max(kept.LSN, writes[i].LSN)
+			}
+			continue
 		}
 
-		log.Printf("ERROR: Failed to delete document [ID: %d]: %v", id, err)
-		return
+		seen[writes[i].ID] = len(collapsed)
+		collapsed = append(collapsed, writes[i])
 	}
 
-	log.Printf("SUCCESS: Document deleted [ID: %d]", id)
+	slices.Reverse(collapsed)
+	return collapsed
 }
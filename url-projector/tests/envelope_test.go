@@ -0,0 +1,244 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"linkfast/url-projector/cdc"
+)
+
+const snowflakeID int64 = 2257190916584378371
+
+const createEvent = `{
+	"schema": {},
+	"payload": {
+		"before": null,
+		"after": {
+			"id": 2257190916584378371,
+			"short_code": "abc1234",
+			"long_url": "https://www.example.com/created",
+			"created_at": "2025-12-07T10:00:00.123456Z",
+			"expires_at": null,
+			"activates_at": "2025-12-08T09:00:00Z",
+			"redirect_type": 301,
+			"max_clicks": 3,
+			"geo_targets": "{\"BR\": \"https://www.example.com/br\"}",
+			"device_targets": "{\"ios\": \"https://apps.apple.com/app/id123\"}",
+			"variants": "[{\"name\": \"a\", \"url\": \"https://www.example.com/a\", \"weight\": 70}, {\"name\": \"b\", \"url\": \"https://www.example.com/b\", \"weight\": 30}]",
+			"sticky_variants": true,
+			"owner_id": 2257190916584378000
+		},
+		"source": {"lsn": 24023128, "ts_ms": 1765101599000},
+		"op": "c",
+		"ts_ms": 1765101600000
+	}
+}`
+
+const deleteEvent = `{
+	"schema": {},
+	"payload": {
+		"before": {
+			"id": 2257190916584378371,
+			"short_code": "abc1234",
+			"long_url": "https://www.example.com/created",
+			"created_at": "2025-12-07T10:00:00Z",
+			"expires_at": null
+		},
+		"after": null,
+		"source": {"lsn": 24023200, "ts_ms": 1765101699000},
+		"op": "d",
+		"ts_ms": 1765101700000
+	}
+}`
+
+const deleteEventWithoutBefore = `{
+	"schema": {},
+	"payload": {"before": null, "after": null, "source": {}, "op": "d", "ts_ms": 1765101700000}
+}`
+
+const recordKey = `{"schema": {"type": "struct"}, "payload": {"id": 2257190916584378371}}`
+
+func TestGetLinkFromAfter_KeepsSnowflakePrecision(t *testing.T) {
+	var envelope cdc.Envelope
+	if err := cdc.ParseMessage([]byte(recordKey), []byte(createEvent), &envelope); err != nil {
+		t.Fatalf("Falha ao parsear o envelope: %v", err)
+	}
+
+	link, err := cdc.GetLinkFromAfter(envelope)
+	if err != nil {
+		t.Fatalf("Falha ao ler 'after': %v", err)
+	}
+
+	if link.ID != snowflakeID {
+		t.Errorf("ID esperado %d, obtido %d", snowflakeID, link.ID)
+	}
+
+	if link.SHORT_CODE != "abc1234" || link.LONG_URL != "https://www.example.com/created" {
+		t.Errorf("Campos inesperados: %+v", link)
+	}
+
+	if link.Domain != "example.com" {
+		t.Errorf("domain esperado example.com, obtido %q", link.Domain)
+	}
+
+	expected := time.Date(2025, 12, 7, 10, 0, 0, 123456000, time.UTC)
+	if !link.CreatedAt.Equal(expected) {
+		t.Errorf("created_at esperado %v, obtido %v", expected, link.CreatedAt)
+	}
+
+	if link.ExpiresAt != nil {
+		t.Errorf("expires_at deveria ser nulo, obtido %v", link.ExpiresAt)
+	}
+
+	activatesAt := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+	if link.ActivatesAt == nil || !link.ActivatesAt.Equal(activatesAt) {
+		t.Errorf("activates_at esperado %v, obtido %v", activatesAt, link.ActivatesAt)
+	}
+
+	if link.RedirectType != 301 {
+		t.Errorf("redirect_type esperado 301, obtido %d", link.RedirectType)
+	}
+
+	if link.MaxClicks == nil || *link.MaxClicks != 3 {
+		t.Errorf("max_clicks esperado 3, obtido %v", link.MaxClicks)
+	}
+
+	if link.GeoTargets["BR"] != "https://www.example.com/br" {
+		t.Errorf("geo_targets esperado com BR, obtido %v", link.GeoTargets)
+	}
+
+	if link.DeviceTargets["ios"] != "https://apps.apple.com/app/id123" {
+		t.Errorf("device_targets esperado com ios, obtido %v", link.DeviceTargets)
+	}
+
+	if len(link.Variants) != 2 || link.Variants[0].Weight != 70 || !link.StickyVariants {
+		t.Errorf("variants esperado com a/b sticky, obtido %+v (sticky %v)", link.Variants, link.StickyVariants)
+	}
+
+	if link.OwnerID == nil || *link.OwnerID != 2257190916584378000 {
+		t.Errorf("owner_id esperado 2257190916584378000, obtido %v", link.OwnerID)
+	}
+}
+
+func TestGetLinkIDFromBefore(t *testing.T) {
+	tests := []struct {
+		description string
+		key         string
+		value       string
+		tombstone   bool
+		shouldFail  bool
+	}{
+		{
+			description: "Delete com 'before' completo (REPLICA IDENTITY FULL)",
+			key:         recordKey,
+			value:       deleteEvent,
+		},
+		{
+			description: "Delete com 'before' limitado à chave primária",
+			key:         recordKey,
+			value:       `{"payload": {"before": {"id": 2257190916584378371}, "after": null, "op": "d"}}`,
+		},
+		{
+			description: "Delete sem 'before' recupera o id pela chave",
+			key:         recordKey,
+			value:       deleteEventWithoutBefore,
+		},
+		{
+			description: "Delete sem 'before' com chave sem schema",
+			key:         `{"id": 2257190916584378371}`,
+			value:       deleteEventWithoutBefore,
+		},
+		{
+			description: "Tombstone do Debezium (valor nulo)",
+			key:         recordKey,
+			value:       "",
+			tombstone:   true,
+		},
+		{
+			description: "Delete sem 'before' e sem chave",
+			key:         "",
+			value:       deleteEventWithoutBefore,
+			shouldFail:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var envelope cdc.Envelope
+			err := cdc.ParseMessage([]byte(test.key), []byte(test.value), &envelope)
+			if err != nil {
+				t.Fatalf("Falha ao parsear a mensagem: %v", err)
+			}
+
+			if envelope.Payload.Op != "d" {
+				t.Errorf("Op esperada 'd', obtida %q", envelope.Payload.Op)
+			}
+
+			if envelope.Tombstone != test.tombstone {
+				t.Errorf("Tombstone esperado %v, obtido %v", test.tombstone, envelope.Tombstone)
+			}
+
+			id, err := cdc.GetLinkIDFromBefore(envelope)
+			if test.shouldFail {
+				if err == nil {
+					t.Errorf("Esperado erro, obtido id %d", id)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Falha ao ler o id: %v", err)
+			}
+
+			if id != snowflakeID {
+				t.Errorf("ID esperado %d, obtido %d", snowflakeID, id)
+			}
+		})
+	}
+}
+
+func TestParseMessage_InvalidPayloads(t *testing.T) {
+	tests := []struct {
+		description string
+		key         string
+		value       string
+	}{
+		{description: "JSON inválido", key: recordKey, value: "{not-json"},
+		{description: "Tombstone sem chave", key: "", value: ""},
+		{description: "Tombstone com chave sem id", key: `{"payload": {"other": 1}}`, value: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var envelope cdc.Envelope
+			if err := cdc.ParseMessage([]byte(test.key), []byte(test.value), &envelope); err == nil {
+				t.Errorf("Esperado erro ao parsear %q", test.value)
+			}
+		})
+	}
+}
+
+func TestGetLinkFromAfter_ReadsSourcePosition(t *testing.T) {
+	var envelope cdc.Envelope
+	if err := cdc.ParseMessage([]byte(recordKey), []byte(createEvent), &envelope); err != nil {
+		t.Fatalf("Falha ao parsear o envelope: %v", err)
+	}
+
+	link, err := cdc.GetLinkFromAfter(envelope)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if link.SourceLSN != 24023128 || link.SourceTsMs != 1765101599000 {
+		t.Errorf("Posição esperada 24023128/1765101599000, obtida %d/%d", link.SourceLSN, link.SourceTsMs)
+	}
+
+	var withoutSource cdc.Envelope
+	if err := cdc.ParseMessage([]byte(recordKey), []byte(deleteEventWithoutBefore), &withoutSource); err != nil {
+		t.Fatalf("Falha ao parsear o envelope: %v", err)
+	}
+
+	if lsn, tsMs := cdc.GetSourcePosition(withoutSource); lsn != 0 || tsMs != 0 {
+		t.Errorf("Posição esperada zerada, obtida %d/%d", lsn, tsMs)
+	}
+}
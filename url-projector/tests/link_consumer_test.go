@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+
+	configs "linkfast/url-projector/config"
+	"linkfast/url-projector/consumer"
+	"linkfast/url-projector/invalidation"
+	"linkfast/url-projector/services"
+)
+
+type fakeDeadLetters struct {
+	published []*kafka.Message
+}
+
+func (f *fakeDeadLetters) Publish(msg *kafka.Message, reason error) error {
+	f.published = append(f.published, msg)
+	return nil
+}
+
+func (f *fakeDeadLetters) Close() {}
+
+func createMessage(id string, offset kafka.Offset) *kafka.Message {
+	topic := "link_fast.link_fast_sc.links"
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Offset: offset},
+		Value:          []byte(strings.Replace(createEvent, "2257190916584378371", id, 1)),
+	}
+}
+
+func TestProcessBatch_DeadLettersOnlyFailingWrites(t *testing.T) {
+	const poisonID int64 = 2257190916584378372
+
+	repo := &fakeLinkRepository{poison: poisonID}
+	service := services.NewLinkService(repo, invalidation.NewNoopPublisher())
+	deadLetters := &fakeDeadLetters{}
+
+	batch := []*kafka.Message{
+		createMessage("2257190916584378371", 10),
+		createMessage("2257190916584378372", 11),
+		createMessage("2257190916584378373", 12),
+		{Value: []byte(`{"payload": `)},
+	}
+
+	if err := consumer.ProcessBatch(batch, configs.KafkaConfig{}, service, deadLetters); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(deadLetters.published) != 2 || deadLetters.published[0] != batch[3] || deadLetters.published[1] != batch[1] {
+		t.Fatalf("Esperado enviar à DLQ apenas a mensagem inválida e a que falhou, obtido %v", deadLetters.published)
+	}
+
+	if len(repo.upserted) != 2 {
+		t.Fatalf("Esperado 2 upserts, obtidos %d", len(repo.upserted))
+	}
+
+	for _, link := range repo.upserted {
+		if link.ID == poisonID {
+			t.Errorf("O link %d não deveria ter sido projetado", poisonID)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"linkfast/url-projector/cdc"
+	"linkfast/url-projector/invalidation"
+	models "linkfast/url-projector/model"
+	"linkfast/url-projector/repositories"
+	"linkfast/url-projector/services"
+	"linkfast/url-projector/utils/consts"
+)
+
+type fakeLinkRepository struct {
+	upserted []models.Link
+	deleted  []int64
+	batches  [][]repositories.LinkWrite
+	stale    int64
+	err      error
+	poison   int64
+}
+
+func (f *fakeLinkRepository) EnsureIndexes(ctx context.Context, expiredRetention, tombstoneRetention time.Duration) error {
+	return nil
+}
+
+func (f *fakeLinkRepository) BulkWrite(ctx context.Context, writes []repositories.LinkWrite) (repositories.BulkResult, error) {
+	if f.err != nil {
+		return repositories.BulkResult{}, f.err
+	}
+
+	for _, write := range writes {
+		if f.poison != 0 && write.ID == f.poison {
+			return repositories.BulkResult{}, errors.New("document failed validation")
+		}
+	}
+
+	f.batches = append(f.batches, writes)
+	for _, write := range writes {
+		switch write.Op {
+		case repositories.WriteUpsert:
+			f.upserted = append(f.upserted, write.Link)
+		case repositories.WriteDelete:
+			f.deleted = append(f.deleted, write.ID)
+		}
+	}
+
+	return repositories.BulkResult{Stale: f.stale}, nil
+}
+
+type fakeInvalidationPublisher struct {
+	published []repositories.LinkWrite
+}
+
+func (f *fakeInvalidationPublisher) Publish(writes []repositories.LinkWrite) {
+	f.published = append(f.published, writes...)
+}
+
+func (f *fakeInvalidationPublisher) Close() {}
+
+func eventWithOp(op string) string {
+	return strings.Replace(createEvent, `"op": "c"`, `"op": "`+op+`"`, 1)
+}
+
+func TestLinkService_ApplyLogic_AllOps(t *testing.T) {
+	tests := []struct {
+		description     string
+		key             string
+		value           string
+		expectedUpserts int
+		expectedDeletes int
+		invalid         bool
+	}{
+		{description: "Op 'c' (create) faz upsert", key: recordKey, value: eventWithOp("c"), expectedUpserts: 1},
+		{description: "Op 'u' (update) faz upsert", key: recordKey, value: eventWithOp("u"), expectedUpserts: 1},
+		{description: "Op 'r' (snapshot) faz upsert", key: recordKey, value: eventWithOp("r"), expectedUpserts: 1},
+		{description: "Op 'd' (delete) remove o documento", key: recordKey, value: deleteEvent, expectedDeletes: 1},
+		{description: "Op 'd' sem 'before' usa a chave", key: recordKey, value: deleteEventWithoutBefore, expectedDeletes: 1},
+		{description: "Tombstone remove o documento", key: recordKey, value: "", expectedDeletes: 1},
+		{description: "Op 'd' sem id é inválida", key: "", value: deleteEventWithoutBefore, invalid: true},
+		{description: "Op 'c' com 'after' inválido é inválida", key: recordKey, value: strings.Replace(createEvent, `"short_code": "abc1234"`, `"short_code": 10`, 1), invalid: true},
+		{description: "Op 't' (truncate) é ignorada", key: recordKey, value: eventWithOp("t")},
+		{description: "Op 'm' (message) é ignorada", key: recordKey, value: eventWithOp("m")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			repo := &fakeLinkRepository{}
+			service := services.NewLinkService(repo, invalidation.NewNoopPublisher())
+
+			var envelope cdc.Envelope
+			if err := cdc.ParseMessage([]byte(test.key), []byte(test.value), &envelope); err != nil {
+				t.Fatalf("Falha ao parsear a mensagem: %v", err)
+			}
+
+			err := service.ApplyLogic(context.Background(), envelope)
+			if test.invalid != errors.Is(err, consts.ErrInvalidEnvelope) {
+				t.Errorf("Erro inesperado: %v", err)
+			}
+
+			if !test.invalid && err != nil {
+				t.Errorf("Erro inesperado: %v", err)
+			}
+
+			if len(repo.upserted) != test.expectedUpserts {
+				t.Errorf("Upserts esperados %d, obtidos %d", test.expectedUpserts, len(repo.upserted))
+			}
+
+			if len(repo.deleted) != test.expectedDeletes {
+				t.Errorf("Deletes esperados %d, obtidos %d", test.expectedDeletes, len(repo.deleted))
+			}
+
+			for _, link := range repo.upserted {
+				if link.ID != snowflakeID {
+					t.Errorf("ID do upsert esperado %d, obtido %d", snowflakeID, link.ID)
+				}
+			}
+
+			for _, id := range repo.deleted {
+				if id != snowflakeID {
+					t.Errorf("ID do delete esperado %d, obtido %d", snowflakeID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkService_ApplyLogic_PropagatesRepositoryErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		value       string
+		repoErr     error
+		expectedErr error
+	}{
+		{description: "Falha no upsert é retornada", value: createEvent, repoErr: consts.ErrInternal, expectedErr: consts.ErrInternal},
+		{description: "Falha no delete é retornada", value: deleteEvent, repoErr: consts.ErrInternal, expectedErr: consts.ErrInternal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			service := services.NewLinkService(&fakeLinkRepository{err: test.repoErr}, invalidation.NewNoopPublisher())
+
+			var envelope cdc.Envelope
+			if err := cdc.ParseMessage([]byte(recordKey), []byte(test.value), &envelope); err != nil {
+				t.Fatalf("Falha ao parsear a mensagem: %v", err)
+			}
+
+			err := service.ApplyLogic(context.Background(), envelope)
+			if !errors.Is(err, test.expectedErr) || (test.expectedErr == nil && err != nil) {
+				t.Errorf("Erro esperado %v, obtido %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestLinkService_ApplyBatch_CollapsesEventsPerID(t *testing.T) {
+	repo := &fakeLinkRepository{}
+	service := services.NewLinkService(repo, invalidation.NewNoopPublisher())
+
+	link := func(id int64, url string) repositories.LinkWrite {
+		return repositories.LinkWrite{
+			Op:   repositories.WriteUpsert,
+			ID:   id,
+			Link: models.Link{ID: id, SHORT_CODE: "code", LONG_URL: url},
+		}
+	}
+
+	writes := []repositories.LinkWrite{
+		link(1, "https://www.example.com/v1"),
+		link(2, "https://www.example.com/other"),
+		link(1, "https://www.example.com/v2"),
+		link(3, "https://www.example.com/removed"),
+		{Op: repositories.WriteDelete, ID: 3},
+		link(1, "https://www.example.com/v3"),
+	}
+
+	if err := service.ApplyBatch(context.Background(), writes); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(repo.batches) != 1 {
+		t.Fatalf("Esperado um único BulkWrite, obtidos %d", len(repo.batches))
+	}
+
+	applied := repo.batches[0]
+	expected := []struct {
+		id  int64
+		op  repositories.WriteOp
+		url string
+	}{
+		{id: 2, op: repositories.WriteUpsert, url: "https://www.example.com/other"},
+		{id: 3, op: repositories.WriteDelete},
+		{id: 1, op: repositories.WriteUpsert, url: "https://www.example.com/v3"},
+	}
+
+	if len(applied) != len(expected) {
+		t.Fatalf("Esperadas %d escritas, obtidas %d: %+v", len(expected), len(applied), applied)
+	}
+
+	for i, want := range expected {
+		if applied[i].ID != want.id || applied[i].Op != want.op || applied[i].Link.LONG_URL != want.url {
+			t.Errorf("Escrita %d esperada %+v, obtida %+v", i, want, applied[i])
+		}
+	}
+}
+
+// The LSN guard itself runs inside MongoDB, so this only checks that the stale writes the
+// repository reports are accumulated.
+func TestLinkService_ApplyBatch_CountsStaleEvents(t *testing.T) {
+	repo := &fakeLinkRepository{stale: 1}
+	service := services.NewLinkService(repo, invalidation.NewNoopPublisher())
+
+	var envelope cdc.Envelope
+	if err := cdc.ParseMessage([]byte(recordKey), []byte(createEvent), &envelope); err != nil {
+		t.Fatalf("Falha ao parsear a mensagem: %v", err)
+	}
+
+	write, err := service.ToWrite(envelope)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if write.LSN != 24023128 {
+		t.Fatalf("LSN esperado 24023128, obtido %d", write.LSN)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := service.ApplyBatch(context.Background(), []repositories.LinkWrite{*write}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+	}
+
+	if service.StaleEvents() != 2 {
+		t.Errorf("Esperados 2 eventos obsoletos, obtidos %d", service.StaleEvents())
+	}
+}
+
+func TestLinkService_ApplyBatch_PublishesInvalidations(t *testing.T) {
+	repo := &fakeLinkRepository{}
+	invalidations := &fakeInvalidationPublisher{}
+	service := services.NewLinkService(repo, invalidations)
+
+	var writes []repositories.LinkWrite
+	for _, value := range []string{deleteEvent, ""} {
+		var envelope cdc.Envelope
+		if err := cdc.ParseMessage([]byte(recordKey), []byte(value), &envelope); err != nil {
+			t.Fatalf("Falha ao parsear a mensagem: %v", err)
+		}
+
+		write, err := service.ToWrite(envelope)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		writes = append(writes, *write)
+	}
+
+	if err := service.ApplyBatch(context.Background(), writes); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(invalidations.published) != 1 {
+		t.Fatalf("Esperada 1 invalidação, obtidas %d", len(invalidations.published))
+	}
+
+	published := invalidations.published[0]
+	if published.Op != repositories.WriteDelete || published.ShortCode != "abc1234" || published.LSN != 24023200 {
+		t.Errorf("O tombstone deveria herdar o código e o LSN do delete, obtido %+v", published)
+	}
+}
@@ -8,4 +8,6 @@ var (
 	ErrInternalDB     = errors.New("internal database error.")
 	ErrInternal       = errors.New("internal error in server.")
 	ErrFieldNull      = errors.New("field is null")
+
+	ErrInvalidEnvelope = errors.New("invalid cdc envelope")
 )
@@ -1,6 +1,10 @@
 package envs
 
-import "os"
+import (
+	"log"
+	"os"
+	"strconv"
+)
 
 func GetEnvWithFallback(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists && value != "" {
@@ -8,3 +12,18 @@ func GetEnvWithFallback(key, fallback string) string {
 	}
 	return fallback
 }
+
+func GetEnvAsIntWithFallback(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Environment variable %s is not a valid integer (%s). Using fallback %d", key, value, fallback)
+		return fallback
+	}
+
+	return parsed
+}
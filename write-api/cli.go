@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"linkfast/write-api/configs"
+	"linkfast/write-api/handlers"
+	"linkfast/write-api/transfer"
+	"linkfast/write-api/utils/consts"
+	"linkfast/write-api/utils/envs"
+	"log"
+	"os"
+)
+
+// runCommand handles the one-shot subcommands, which talk to the database directly instead
+// of going through the HTTP API:
+//
+//	write_api import --format csv|jsonl --file links.csv
+//	write_api export --format csv|jsonl [--file links.csv]
+func runCommand(name string, args []string) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	format := flags.String("format", transfer.FormatCSV, "file format, csv or jsonl")
+	file := flags.String("file", "-", "file to read or write, - for stdin/stdout")
+
+	switch name {
+	case "import", "export":
+	default:
+		return fmt.Errorf("unknown command %q, expected import or export", name)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != transfer.FormatCSV && *format != transfer.FormatJSONL {
+		return consts.ErrUnknownFormat
+	}
+
+	db := configs.ConnectDB()
+	linkService := newLinkService(db)
+
+	if name == "export" {
+		var out io.Writer = os.Stdout
+		if *file != "-" {
+			f, err := os.Create(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		total, err := transfer.NewExporter(linkService).Export(out, *format)
+		if err != nil {
+			return err
+		}
+		log.Printf("Exported %d links", total)
+		return nil
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	reader, err := transfer.NewReader(in, *format)
+	if err != nil {
+		return err
+	}
+
+	chunkSize := envs.GetEnvAsIntWithFallback("IMPORT_CHUNK_SIZE", transfer.DefaultChunkSize)
+	report, importErr := transfer.NewImporter(linkService, handlers.ValidateImportLink, chunkSize).Import(reader, nil, nil, true)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	return importErr
+}
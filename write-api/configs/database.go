@@ -50,6 +50,7 @@ func ConnectDB() *gorm.DB {
 
 	db, err := gorm.Open(postgres.Open(PG_URL), &gorm.Config{
 		// Ex: Logger: logger.Default.LogMode(logger.Info),
+		TranslateError: true,
 	})
 
 	if err != nil {
@@ -126,6 +127,11 @@ func PostMigrationSetup(db *gorm.DB) error {
 		log.Printf("Failed to create PUBLICATION for links.: %v", err)
 	}
 
+	fmt.Println("Setting replica identity...")
+	if err := db.Exec("ALTER TABLE link_fast_sc.links REPLICA IDENTITY FULL;").Error; err != nil {
+		log.Printf("Failed to set REPLICA IDENTITY FULL in the links table.: %v", err)
+	}
+
 	fmt.Println("Setting table owner...")
 	if err := db.Exec("ALTER TABLE link_fast_sc.links OWNER TO replication_group;").Error; err != nil {
 		log.Printf("Failed to change OWNER in the links table.: %v", err)
@@ -239,10 +245,15 @@ func Migrate(db *gorm.DB) error {
 		log.Printf("failed to create schema 'link_fast_sc': %v", err)
 	}
 
+	err = db.Exec("CREATE SEQUENCE IF NOT EXISTS link_fast_sc.short_code_seq;").Error
+	if err != nil {
+		log.Printf("failed to create sequence 'link_fast_sc.short_code_seq': %v", err)
+	}
+
 	ConfiguredCDC(db)
 
 	log.Println("Running migrations...")
-	db.AutoMigrate(&models.Links{})
+	db.AutoMigrate(&models.Links{}, &models.ApiKey{}, &models.User{})
 	PostMigrationSetup(db)
 
 	RegisterDebeziumConnector()
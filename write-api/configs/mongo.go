@@ -0,0 +1,33 @@
+package configs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ConnectMongo opens the read model, which holds the click counters kept by read-api.
+func ConnectMongo(uri string) (*mongo.Client, error) {
+	if uri == "" {
+		return nil, fmt.Errorf("MONGO_URI not defined")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	log.Println("MongoDB connected successfully!")
+	return client, nil
+}
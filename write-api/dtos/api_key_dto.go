@@ -0,0 +1,23 @@
+package dtos
+
+import "time"
+
+type CreateApiKeyDto struct {
+	Name   string   `json:"name" validate:"required,min=3,max=64"`
+	Scopes []string `json:"scopes" validate:"required,min=1,unique,dive,apikeyscope"`
+}
+
+type ApiKeyDto struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// IssuedApiKeyDto is only returned when the key is created; the key itself cannot be read back later.
+type IssuedApiKeyDto struct {
+	ApiKeyDto
+	Key string `json:"key"`
+}
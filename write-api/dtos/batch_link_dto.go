@@ -0,0 +1,31 @@
+package dtos
+
+const (
+	BatchModeAtomic  = "atomic"
+	BatchModePartial = "partial"
+
+	BatchItemCreated  = "created"
+	BatchItemInvalid  = "invalid"
+	BatchItemConflict = "conflict"
+	BatchItemFailed   = "failed"
+	BatchItemSkipped  = "skipped"
+)
+
+type CreateLinksBatchDto struct {
+	Mode  string          `json:"mode" validate:"omitempty,oneof=atomic partial"`
+	Links []CreateLinkDto `json:"links" validate:"required,min=1,max=1000"`
+}
+
+type BatchItemResultDto struct {
+	Index  int      `json:"index"`
+	Status string   `json:"status"`
+	Link   *LinkDto `json:"link,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+type BatchResultDto struct {
+	Mode    string               `json:"mode"`
+	Created int                  `json:"created"`
+	Failed  int                  `json:"failed"`
+	Results []BatchItemResultDto `json:"results"`
+}
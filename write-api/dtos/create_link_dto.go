@@ -3,6 +3,20 @@ package dtos
 import "time"
 
 type CreateLinkDto struct {
-	LONG_URL  string     `json:"long_url" validate:"required,min=8,max=2500"`
-	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty,gt=now"`
+	LONG_URL       string            `json:"long_url" validate:"required,min=8,max=2500"`
+	ExpiresAt      *time.Time        `json:"expires_at" validate:"omitempty,gt=now"`
+	ActivatesAt    *time.Time        `json:"activates_at" validate:"omitempty,gt=now"`
+	Alias          *string           `json:"alias" validate:"omitempty,min=3,max=32,alias,notreserved"`
+	RedirectType   *int              `json:"redirect_type" validate:"omitempty,oneof=301 302 307 308"`
+	Password       *string           `json:"password" validate:"omitempty,min=4,max=72"`
+	MaxClicks      *int              `json:"max_clicks" validate:"omitempty,min=1"`
+	GeoTargets     map[string]string `json:"geo_targets" validate:"omitempty,max=250,dive,keys,iso3166_1_alpha2,endkeys,min=8,max=2500"`
+	DeviceTargets  map[string]string `json:"device_targets" validate:"omitempty,dive,keys,devicetarget,endkeys,min=8,max=2500"`
+	Variants       []VariantDto      `json:"variants" validate:"omitempty,min=2,max=10,unique=Name,dive"`
+	StickyVariants bool              `json:"sticky_variants"`
+	CreatedByKeyID *int64            `json:"-"`
+	OwnerID        *int64            `json:"-"`
+	// PasswordHash is only set by the importer, so an exported bcrypt hash is restored as
+	// is. Password takes precedence when both are present.
+	PasswordHash *string `json:"-"`
 }
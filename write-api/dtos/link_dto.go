@@ -3,9 +3,20 @@ package dtos
 import "time"
 
 type LinkDto struct {
-	ID         int64      `json:"id"`
-	SHORT_CODE string     `json:"short_code"`
-	LONG_URL   string     `json:"long_url"`
-	CreatedAt  time.Time  `json:"created_at"`
-	ExpiresAt  *time.Time `json:"expires_at"`
+	ID                int64             `json:"id"`
+	SHORT_CODE        string            `json:"short_code"`
+	LONG_URL          string            `json:"long_url"`
+	CreatedAt         time.Time         `json:"created_at"`
+	ExpiresAt         *time.Time        `json:"expires_at"`
+	ActivatesAt       *time.Time        `json:"activates_at"`
+	RedirectType      int               `json:"redirect_type"`
+	PasswordProtected bool              `json:"password_protected"`
+	MaxClicks         *int              `json:"max_clicks"`
+	RemainingClicks   *int              `json:"remaining_clicks,omitempty"`
+	GeoTargets        map[string]string `json:"geo_targets"`
+	DeviceTargets     map[string]string `json:"device_targets"`
+	Variants          []VariantDto      `json:"variants"`
+	StickyVariants    bool              `json:"sticky_variants"`
+	CreatedByKeyID    *int64            `json:"created_by_key_id"`
+	OwnerID           *int64            `json:"owner_id"`
 }
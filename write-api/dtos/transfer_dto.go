@@ -0,0 +1,14 @@
+package dtos
+
+type ImportLineErrorDto struct {
+	Line   int      `json:"line"`
+	Errors []string `json:"errors"`
+}
+
+type ImportReportDto struct {
+	Total           int                  `json:"total"`
+	Created         int                  `json:"created"`
+	Failed          int                  `json:"failed"`
+	Errors          []ImportLineErrorDto `json:"errors"`
+	ErrorsTruncated bool                 `json:"errors_truncated"`
+}
@@ -0,0 +1,22 @@
+package dtos
+
+import "time"
+
+type UpdateLinkDto struct {
+	LONG_URL         *string           `json:"long_url" validate:"omitempty,min=8,max=2500"`
+	ExpiresAt        *time.Time        `json:"expires_at" validate:"omitempty,gt=now,excluded_with=ClearExpiresAt"`
+	ClearExpiresAt   bool              `json:"clear_expires_at"`
+	ActivatesAt      *time.Time        `json:"activates_at" validate:"omitempty,gt=now,excluded_with=ClearActivatesAt"`
+	ClearActivatesAt bool              `json:"clear_activates_at"`
+	RedirectType     *int              `json:"redirect_type" validate:"omitempty,oneof=301 302 307 308"`
+	Password         *string           `json:"password" validate:"omitempty,min=4,max=72,excluded_with=RemovePassword"`
+	RemovePassword   bool              `json:"remove_password"`
+	GeoTargets       map[string]string `json:"geo_targets" validate:"omitempty,max=250,dive,keys,iso3166_1_alpha2,endkeys,min=8,max=2500"`
+	DeviceTargets    map[string]string `json:"device_targets" validate:"omitempty,dive,keys,devicetarget,endkeys,min=8,max=2500"`
+	Variants         []VariantDto      `json:"variants" validate:"omitempty,min=2,max=10,unique=Name,dive"`
+	StickyVariants   *bool             `json:"sticky_variants"`
+}
+
+func (d UpdateLinkDto) IsEmpty() bool {
+	return d.LONG_URL == nil && d.ExpiresAt == nil && !d.ClearExpiresAt && d.ActivatesAt == nil && !d.ClearActivatesAt && d.RedirectType == nil && d.Password == nil && !d.RemovePassword && d.GeoTargets == nil && d.DeviceTargets == nil && d.Variants == nil && d.StickyVariants == nil
+}
@@ -0,0 +1,7 @@
+package dtos
+
+type VariantDto struct {
+	Name   string `json:"name" validate:"required,max=32,alias"`
+	URL    string `json:"url" validate:"required,min=8,max=2500"`
+	Weight int    `json:"weight" validate:"required,min=1,max=1000"`
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"linkfast/write-api/middlewares"
+	"linkfast/write-api/models"
+	"linkfast/write-api/utils/res"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// callerKeyID is the id of the API key that authenticated the request, or nil when it was
+// a user or the route is not behind the authentication middleware.
+func callerKeyID(c *fiber.Ctx) *int64 {
+	key := middlewares.CurrentApiKey(c)
+	if key == nil {
+		return nil
+	}
+	return &key.ID
+}
+
+func callerUserID(c *fiber.Ctx) *int64 {
+	user := middlewares.CurrentUser(c)
+	if user == nil {
+		return nil
+	}
+	return &user.UserID
+}
+
+// canAccess lets users reach only the links they own, unless they are admins. API keys are
+// operator credentials and reach every link, as do routes outside the authentication middleware.
+func canAccess(c *fiber.Ctx, link models.Links) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil || user.IsAdmin() {
+		return true
+	}
+	return link.OwnerID != nil && *link.OwnerID == user.UserID
+}
+
+func forbidden(c *fiber.Ctx, traceID string) error {
+	return c.Status(fiber.StatusForbidden).JSON(
+		res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      fiber.StatusForbidden,
+			Status:    false,
+			Message:   "You do not have access to this link",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
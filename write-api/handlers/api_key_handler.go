@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/services"
+	"linkfast/write-api/utils/consts"
+	"linkfast/write-api/utils/res"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jinzhu/copier"
+)
+
+type ApiKeyHandler interface {
+	Create(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	Revoke(c *fiber.Ctx) error
+}
+
+type apiKeyHandler struct {
+	service services.ApiKeyService
+}
+
+func NewApiKeyHandler(service services.ApiKeyService) ApiKeyHandler {
+	return &apiKeyHandler{service: service}
+}
+
+func (h *apiKeyHandler) Create(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	var req dtos.CreateApiKeyDto
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err.Error(),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if err := validater.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[[]string]{
+				Timestamp: time.Now(),
+				Payload:   validationMessages(err),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	key, plain, err := h.service.Issue(req)
+	if err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	issued := dtos.IssuedApiKeyDto{Key: plain}
+	if err := copier.Copy(&issued.ApiKeyDto, key); err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(
+		res.ResponseHttp[dtos.IssuedApiKeyDto]{
+			Timestamp: time.Now(),
+			Payload:   issued,
+			Code:      fiber.StatusCreated,
+			Status:    true,
+			Message:   "API key created, store it now as it cannot be retrieved again",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
+
+func (h *apiKeyHandler) List(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	keys, err := h.service.List()
+	if err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	payload := []dtos.ApiKeyDto{}
+	if err := copier.Copy(&payload, &keys); err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		res.ResponseHttp[[]dtos.ApiKeyDto]{
+			Timestamp: time.Now(),
+			Payload:   payload,
+			Code:      fiber.StatusOK,
+			Status:    true,
+			Message:   "API keys found",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
+
+func (h *apiKeyHandler) Revoke(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	id, err := c.ParamsInt("id", 0)
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   c.Params("id"),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Id is required",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	key, err := h.service.Revoke(int64(id))
+	if errors.Is(err, consts.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   "",
+				Code:      fiber.StatusNotFound,
+				Status:    false,
+				Message:   "API key not found",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	var dto dtos.ApiKeyDto
+	if err := copier.Copy(&dto, key); err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		res.ResponseHttp[dtos.ApiKeyDto]{
+			Timestamp: time.Now(),
+			Payload:   dto,
+			Code:      fiber.StatusOK,
+			Status:    true,
+			Message:   "API key revoked",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
+
+func internalError(c *fiber.Ctx, traceID string, err error) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(
+		res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   err.Error(),
+			Code:      fiber.StatusInternalServerError,
+			Status:    false,
+			Message:   "Error internal in server! Try again later",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
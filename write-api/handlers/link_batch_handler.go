@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/utils/consts"
+	"linkfast/write-api/utils/res"
+	"log"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jinzhu/copier"
+)
+
+// CreateBatch creates up to 1000 links per request. In the default atomic mode either every
+// link is created or none is; in partial mode the valid links are created and each item
+// reports its own outcome.
+func (h *linkHandler) CreateBatch(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	var req dtos.CreateLinksBatchDto
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err.Error(),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if err := validater.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[[]string]{
+				Timestamp: time.Now(),
+				Payload:   validationMessages(err),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if req.Mode == "" {
+		req.Mode = dtos.BatchModeAtomic
+	}
+	atomic := req.Mode == dtos.BatchModeAtomic
+
+	result := dtos.BatchResultDto{
+		Mode:    req.Mode,
+		Results: make([]dtos.BatchItemResultDto, len(req.Links)),
+	}
+
+	valid := make([]dtos.CreateLinkDto, 0, len(req.Links))
+	positions := make([]int, 0, len(req.Links))
+	createdBy := callerKeyID(c)
+	owner := callerUserID(c)
+
+	for i, item := range req.Links {
+		result.Results[i].Index = i
+
+		if err := validater.Struct(item); err != nil {
+			result.Results[i].Status = dtos.BatchItemInvalid
+			result.Results[i].Errors = validationMessages(err)
+			continue
+		}
+
+		item.CreatedByKeyID = createdBy
+		item.OwnerID = owner
+		valid = append(valid, item)
+		positions = append(positions, i)
+	}
+
+	if atomic && len(valid) < len(req.Links) {
+		return h.batchResponse(c, traceID, fiber.StatusBadRequest, "Batch rejected, no link was created", skipPending(result))
+	}
+
+	items, err := h.service.CreateBatch(valid, atomic)
+	if err != nil && !errors.Is(err, consts.ErrBatchRejected) {
+		if errors.Is(err, consts.ErrConflict) {
+			return h.batchResponse(c, traceID, fiber.StatusConflict, "Batch rejected, no link was created", skipPending(result))
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err.Error(),
+				Code:      fiber.StatusInternalServerError,
+				Status:    false,
+				Message:   "Error internal in server! Try again later",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	conflicts := 0
+	for j, i := range positions {
+		item := &result.Results[i]
+
+		switch {
+		case items[j].Link != nil:
+			var dto dtos.LinkDto
+			if err := copier.Copy(&dto, items[j].Link); err != nil {
+				log.Printf("Error the copy of Links to LinkDto: %v", err)
+			}
+			item.Status = dtos.BatchItemCreated
+			item.Link = &dto
+		case errors.Is(items[j].Err, consts.ErrConflict):
+			conflicts++
+			item.Status = dtos.BatchItemConflict
+			item.Errors = []string{"Alias already in use"}
+		case errors.Is(items[j].Err, consts.ErrInvalidWindow):
+			item.Status = dtos.BatchItemInvalid
+			item.Errors = []string{items[j].Err.Error()}
+		case items[j].Err != nil:
+			item.Status = dtos.BatchItemFailed
+			item.Errors = []string{items[j].Err.Error()}
+		}
+	}
+
+	if errors.Is(err, consts.ErrBatchRejected) {
+		code := fiber.StatusBadRequest
+		if conflicts > 0 {
+			code = fiber.StatusConflict
+		}
+		return h.batchResponse(c, traceID, code, "Batch rejected, no link was created", skipPending(result))
+	}
+
+	code := fiber.StatusCreated
+	message := "Links created"
+	for _, item := range result.Results {
+		if item.Status != dtos.BatchItemCreated {
+			code = fiber.StatusMultiStatus
+			message = "Batch partially created"
+			break
+		}
+	}
+
+	return h.batchResponse(c, traceID, code, message, result)
+}
+
+func (h *linkHandler) batchResponse(c *fiber.Ctx, traceID string, code int, message string, result dtos.BatchResultDto) error {
+	for _, item := range result.Results {
+		if item.Status == dtos.BatchItemCreated {
+			result.Created++
+		} else {
+			result.Failed++
+		}
+	}
+
+	return c.Status(code).JSON(
+		res.ResponseHttp[dtos.BatchResultDto]{
+			Timestamp: time.Now(),
+			Payload:   result,
+			Code:      code,
+			Status:    code == fiber.StatusCreated || code == fiber.StatusMultiStatus,
+			Message:   message,
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
+
+// skipPending marks the items that had nothing wrong with them but were not created because the atomic batch was rejected.
+func skipPending(result dtos.BatchResultDto) dtos.BatchResultDto {
+	for i := range result.Results {
+		if result.Results[i].Status == "" {
+			result.Results[i].Status = dtos.BatchItemSkipped
+		}
+	}
+	return result
+}
+
+// ValidateCreateLink applies the same rules as the create endpoint and returns the failures, if any.
+func ValidateCreateLink(link dtos.CreateLinkDto) []string {
+	if err := validater.Struct(link); err != nil {
+		return validationMessages(err)
+	}
+	return nil
+}
+
+// ValidateImportLink is ValidateCreateLink without the gt=now rules on expires_at and
+// activates_at: an exported link that already expired or went live must import back as is.
+func ValidateImportLink(link dtos.CreateLinkDto) []string {
+	if err := validater.StructExcept(link, "ExpiresAt", "ActivatesAt"); err != nil {
+		return validationMessages(err)
+	}
+	return nil
+}
+
+func validationMessages(err error) []string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+
+	messages := []string{}
+	for _, fieldErr := range validationErrors {
+		messages = append(messages, fieldErr.Field()+" failed on "+fieldErr.Tag())
+	}
+	return messages
+}
@@ -22,6 +22,10 @@ type LinkHandler interface {
 	GetByID(c *fiber.Ctx) error
 	Delete(c *fiber.Ctx) error
 	GetByShotCode(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	CreateBatch(c *fiber.Ctx) error
+	Import(c *fiber.Ctx) error
+	Export(c *fiber.Ctx) error
 }
 
 type linkHandler struct {
@@ -45,7 +49,7 @@ func (h *linkHandler) GetByID(c *fiber.Ctx) error {
 	if err != nil || id <= 0 {
 		response := res.ResponseHttp[string]{
 			Timestamp: time.Now(),
-			Payload:   err.Error(),
+			Payload:   idStr,
 			Code:      fiber.StatusBadRequest,
 			Status:    false,
 			Message:   "Id is required",
@@ -81,6 +85,10 @@ func (h *linkHandler) GetByID(c *fiber.Ctx) error {
 		return c.Status(response.Code).JSON(response)
 	}
 
+	if !canAccess(c, link) {
+		return forbidden(c, traceID)
+	}
+
 	err_copy := copier.Copy(&dto, link)
 	if err_copy != nil {
 		log.Printf("Error the copy of Links to LinkDto: %v", err_copy)
@@ -98,6 +106,12 @@ func (h *linkHandler) GetByID(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(response)
 	}
 
+	remaining, err_count := h.service.RemainingClicks(link)
+	if err_count != nil {
+		log.Printf("Error reading the remaining clicks of link %d: %v", link.ID, err_count)
+	}
+	dto.RemainingClicks = remaining
+
 	response := res.ResponseHttp[dtos.LinkDto]{
 		Timestamp: time.Now(),
 		Payload:   dto,
@@ -146,6 +160,10 @@ func (h *linkHandler) GetByShotCode(c *fiber.Ctx) error {
 		return c.Status(response.Code).JSON(response)
 	}
 
+	if !canAccess(c, *link) {
+		return forbidden(c, traceID)
+	}
+
 	err_parse := copier.Copy(&dto, link)
 	if err_parse != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -220,12 +238,15 @@ func (h *linkHandler) Create(c *fiber.Ctx) error {
 		)
 	}
 
+	req.CreatedByKeyID = callerKeyID(c)
+	req.OwnerID = callerUserID(c)
+
 	body, err_create := h.service.Create(req)
 	if err_create != nil {
 		res := res.ResponseHttp[string]{
 			Timestamp: time.Now(),
 			Payload:   err_create.Error(),
-			Code:      1,
+			Code:      fiber.StatusInternalServerError,
 			Status:    false,
 			Message:   err_create.Error(),
 			Version:   1,
@@ -233,11 +254,16 @@ func (h *linkHandler) Create(c *fiber.Ctx) error {
 			Path:      "",
 		}
 
-		if errors.Is(err_create, consts.ErrInternal) {
-			res.Code = fiber.StatusInternalServerError
+		if errors.Is(err_create, consts.ErrConflict) {
+			res.Code = fiber.StatusConflict
+			res.Message = "Alias already in use"
 		}
 
-		return c.Status(1).JSON(res)
+		if errors.Is(err_create, consts.ErrInvalidWindow) {
+			res.Code = fiber.StatusBadRequest
+		}
+
+		return c.Status(res.Code).JSON(res)
 	}
 
 	err_parse := copier.Copy(dto, body)
@@ -281,7 +307,7 @@ func (h *linkHandler) Delete(c *fiber.Ctx) error {
 	if err != nil || id <= 0 {
 		response := res.ResponseHttp[string]{
 			Timestamp: time.Now(),
-			Payload:   err.Error(),
+			Payload:   idStr,
 			Code:      fiber.StatusBadRequest,
 			Status:    false,
 			Message:   "Id is required",
@@ -313,6 +339,10 @@ func (h *linkHandler) Delete(c *fiber.Ctx) error {
 		return c.Status(response.Code).JSON(response)
 	}
 
+	if !canAccess(c, link) {
+		return forbidden(c, traceID)
+	}
+
 	err_delete := h.service.Delete(&link)
 	if err_delete != nil {
 		response := res.ResponseHttp[string]{
@@ -342,3 +372,155 @@ func (h *linkHandler) Delete(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
+
+func (h *linkHandler) Update(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	idStr := c.Params("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		response := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   idStr,
+			Code:      fiber.StatusBadRequest,
+			Status:    false,
+			Message:   "Id is required",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	var req dtos.UpdateLinkDto
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err.Error(),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if req.IsEmpty() {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   "",
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "No fields to update",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if err := validater.Struct(req); err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, err.Field()+" failed on "+err.Tag())
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[[]string]{
+				Timestamp: time.Now(),
+				Payload:   errors,
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	link, err_get := h.service.GetByID(id)
+	if err_get != nil {
+		response := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   err_get.Error(),
+			Code:      fiber.StatusInternalServerError,
+			Status:    false,
+			Message:   err_get.Error(),
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		if errors.Is(err_get, consts.ErrRecordNotFound) {
+			response.Code = fiber.StatusNotFound
+		}
+
+		return c.Status(response.Code).JSON(response)
+	}
+
+	if !canAccess(c, link) {
+		return forbidden(c, traceID)
+	}
+
+	updated, err_update := h.service.Update(&link, req)
+	if err_update != nil {
+		response := res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   err_update.Error(),
+			Code:      fiber.StatusInternalServerError,
+			Status:    false,
+			Message:   err_update.Error(),
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		}
+
+		if errors.Is(err_update, consts.ErrRecordNotFound) {
+			response.Code = fiber.StatusNotFound
+		}
+
+		if errors.Is(err_update, consts.ErrInvalidWindow) {
+			response.Code = fiber.StatusBadRequest
+		}
+
+		return c.Status(response.Code).JSON(response)
+	}
+
+	var dto dtos.LinkDto
+	if err_parse := copier.Copy(&dto, updated); err_parse != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err_parse.Error(),
+				Code:      fiber.StatusInternalServerError,
+				Status:    false,
+				Message:   "Error internal in server",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	response := res.ResponseHttp[dtos.LinkDto]{
+		Timestamp: time.Now(),
+		Payload:   dto,
+		Code:      fiber.StatusOK,
+		Status:    true,
+		Message:   "Link updated",
+		Version:   1,
+		TraceID:   traceID,
+		Path:      "",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/middlewares"
+	"linkfast/write-api/transfer"
+	"linkfast/write-api/utils/consts"
+	"linkfast/write-api/utils/res"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Import streams a CSV or JSONL file of links into the table and reports the lines that
+// could not be imported. The format comes from ?format=, falling back to the Content-Type.
+func (h *linkHandler) Import(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	var body io.Reader = c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	reader, err := transfer.NewReader(body, importFormat(c))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err.Error(),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	// Like the export, owners in the file are only honoured for API keys and admins.
+	user := middlewares.CurrentUser(c)
+	keepOwners := user == nil || user.IsAdmin()
+
+	report, err := transfer.NewImporter(h.service, ValidateImportLink, transfer.DefaultChunkSize).Import(reader, callerKeyID(c), callerUserID(c), keepOwners)
+	if err != nil {
+		code := fiber.StatusInternalServerError
+		message := "Import interrupted, the links reported as created were kept"
+		if errors.Is(err, consts.ErrInvalidImport) {
+			code = fiber.StatusBadRequest
+		}
+		log.Printf("Error importing links: %v", err)
+
+		return c.Status(code).JSON(
+			res.ResponseHttp[dtos.ImportReportDto]{
+				Timestamp: time.Now(),
+				Payload:   report,
+				Code:      code,
+				Status:    false,
+				Message:   message + ": " + err.Error(),
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	code := fiber.StatusOK
+	message := "Links imported"
+	if report.Failed > 0 {
+		code = fiber.StatusMultiStatus
+		message = "Links partially imported"
+	}
+
+	return c.Status(code).JSON(
+		res.ResponseHttp[dtos.ImportReportDto]{
+			Timestamp: time.Now(),
+			Payload:   report,
+			Code:      code,
+			Status:    true,
+			Message:   message,
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
+
+// Export streams every link as CSV or JSONL (?format=, csv by default), so it is limited
+// to API keys and admins. Once the first bytes are sent the status can no longer change,
+// so a failure midway is only logged and the output ends early.
+func (h *linkHandler) Export(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	if user := middlewares.CurrentUser(c); user != nil && !user.IsAdmin() {
+		return forbidden(c, traceID)
+	}
+
+	format := strings.ToLower(c.Query("format", transfer.FormatCSV))
+
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case transfer.FormatCSV:
+	case transfer.FormatJSONL:
+		contentType = "application/x-ndjson"
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   consts.ErrUnknownFormat.Error(),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="links.`+format+`"`)
+
+	exporter := transfer.NewExporter(h.service)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		total, err := exporter.Export(w, format)
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("Error exporting links after %d rows (trace %s): %v", total, traceID, err)
+		}
+	})
+
+	return nil
+}
+
+func importFormat(c *fiber.Ctx) string {
+	if format := c.Query("format"); format != "" {
+		return strings.ToLower(format)
+	}
+
+	contentType := strings.ToLower(c.Get(fiber.HeaderContentType))
+	if strings.Contains(contentType, "json") {
+		return transfer.FormatJSONL
+	}
+	return transfer.FormatCSV
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/services"
+	"linkfast/write-api/utils/consts"
+	"linkfast/write-api/utils/res"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jinzhu/copier"
+)
+
+type UserHandler interface {
+	Register(c *fiber.Ctx) error
+	Login(c *fiber.Ctx) error
+}
+
+type userHandler struct {
+	service services.UserService
+}
+
+func NewUserHandler(service services.UserService) UserHandler {
+	return &userHandler{service: service}
+}
+
+func (h *userHandler) Register(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	var req dtos.RegisterUserDto
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err.Error(),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if err := validater.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[[]string]{
+				Timestamp: time.Now(),
+				Payload:   validationMessages(err),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	user, err := h.service.Register(req)
+	if errors.Is(err, consts.ErrConflict) {
+		return c.Status(fiber.StatusConflict).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   "",
+				Code:      fiber.StatusConflict,
+				Status:    false,
+				Message:   "Email already registered",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	var dto dtos.UserDto
+	if err := copier.Copy(&dto, user); err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(
+		res.ResponseHttp[dtos.UserDto]{
+			Timestamp: time.Now(),
+			Payload:   dto,
+			Code:      fiber.StatusCreated,
+			Status:    true,
+			Message:   "User registered",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
+
+func (h *userHandler) Login(c *fiber.Ctx) error {
+	traceID, ok := c.Locals("trace_id").(string)
+	if !ok {
+		traceID = "unknown_trace"
+	}
+
+	var req dtos.LoginDto
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   err.Error(),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if err := validater.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			res.ResponseHttp[[]string]{
+				Timestamp: time.Now(),
+				Payload:   validationMessages(err),
+				Code:      fiber.StatusBadRequest,
+				Status:    false,
+				Message:   "Inputs invalids",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	user, token, expiresAt, err := h.service.Login(req)
+	if errors.Is(err, consts.ErrInvalidCredentials) {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			res.ResponseHttp[string]{
+				Timestamp: time.Now(),
+				Payload:   "",
+				Code:      fiber.StatusUnauthorized,
+				Status:    false,
+				Message:   "Invalid email or password",
+				Version:   1,
+				TraceID:   traceID,
+				Path:      "",
+			},
+		)
+	}
+
+	if err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	payload := dtos.TokenDto{Token: token, TokenType: "Bearer", ExpiresAt: expiresAt}
+	if err := copier.Copy(&payload.User, user); err != nil {
+		return internalError(c, traceID, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		res.ResponseHttp[dtos.TokenDto]{
+			Timestamp: time.Now(),
+			Payload:   payload,
+			Code:      fiber.StatusOK,
+			Status:    true,
+			Message:   "Logged in",
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
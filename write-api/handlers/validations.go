@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"linkfast/write-api/utils/consts"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func init() {
+	validater.RegisterValidation("alias", func(fl validator.FieldLevel) bool {
+		return aliasPattern.MatchString(fl.Field().String())
+	})
+
+	validater.RegisterValidation("apikeyscope", func(fl validator.FieldLevel) bool {
+		return slices.Contains(consts.ApiKeyScopes, fl.Field().String())
+	})
+
+	validater.RegisterValidation("devicetarget", func(fl validator.FieldLevel) bool {
+		return slices.Contains(consts.DeviceTargetKeys, fl.Field().String())
+	})
+
+	validater.RegisterValidation("notreserved", func(fl validator.FieldLevel) bool {
+		return !slices.Contains(consts.ReservedAliases, strings.ToLower(fl.Field().String()))
+	})
+}
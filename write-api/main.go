@@ -1,23 +1,38 @@
 package main
 
 import (
+	"crypto/rand"
+	"linkfast/shared/auth"
 	"linkfast/write-api/configs"
 	"linkfast/write-api/handlers"
+	"linkfast/write-api/middlewares"
 	"linkfast/write-api/repositories"
 	"linkfast/write-api/routers"
 	"linkfast/write-api/services"
+	"linkfast/write-api/utils/envs"
 	"log"
+	"os"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"gorm.io/gorm"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	log.Print("Waiting the another container startup.........")
 	time.Sleep(20 * time.Second)
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		StreamRequestBody: true,
+	})
 
 	configs.ConnectDB()
 	db := configs.DB
@@ -28,11 +43,63 @@ func main() {
 
 	configs.Migrate(db)
 
-	linkRepository := repositories.NewLinkRepository(db)
-	linkService := services.NewLinkService(linkRepository)
+	apiKeyService := services.NewApiKeyService(repositories.NewApiKeyRepository(db))
+	if bootstrapKey := envs.GetEnvWithFallback("BOOTSTRAP_API_KEY", ""); bootstrapKey != "" {
+		if err := apiKeyService.Bootstrap(bootstrapKey); err != nil {
+			log.Fatalf("Invalid bootstrap api key: %v", err)
+		}
+	}
+
+	jwtSecret := []byte(envs.GetEnvWithFallback("JWT_SECRET", ""))
+	if len(jwtSecret) == 0 {
+		log.Print("WARN: JWT_SECRET not defined, using a random secret, user tokens will not survive a restart")
+		jwtSecret = make([]byte, 32)
+		if _, err := rand.Read(jwtSecret); err != nil {
+			log.Fatalf("Failed to generate the JWT secret: %v", err)
+		}
+	}
+
+	tokens := auth.NewTokenIssuer(jwtSecret, time.Duration(envs.GetEnvAsIntWithFallback("JWT_TTL_SECONDS", 3600))*time.Second)
+	userService := services.NewUserService(repositories.NewUserRepository(db), tokens)
+	if adminEmail := envs.GetEnvWithFallback("BOOTSTRAP_ADMIN_EMAIL", ""); adminEmail != "" {
+		if err := userService.Bootstrap(adminEmail, envs.GetEnvWithFallback("BOOTSTRAP_ADMIN_PASSWORD", "")); err != nil {
+			log.Fatalf("Failed to register the bootstrap admin: %v", err)
+		}
+	}
+
+	routers.AuthRoute(app, handlers.NewUserHandler(userService))
+
+	app.Use(middlewares.Authenticate(apiKeyService, userService))
+
+	linkService := newLinkService(db)
 	linkHandler := handlers.NewLinkHandler(linkService)
 
 	routers.LinkRoute(app, linkHandler)
+	routers.ApiKeyRoute(app, handlers.NewApiKeyHandler(apiKeyService))
 
 	app.Listen(":8888")
 }
+
+func newLinkService(db *gorm.DB) services.LinkService {
+	generator, err := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy:   envs.GetEnvWithFallback("SHORT_CODE_STRATEGY", repositories.StrategyRandom),
+		Length:     envs.GetEnvAsIntWithFallback("SHORT_CODE_LENGTH", 7),
+		MaxRetries: envs.GetEnvAsIntWithFallback("SHORT_CODE_MAX_RETRIES", 5),
+		Salt:       envs.GetEnvWithFallback("SHORT_CODE_SALT", ""),
+		Sequence:   repositories.NewPostgresSequence(db),
+	})
+	if err != nil {
+		log.Fatalf("Invalid short code generator configuration: %v", err)
+	}
+
+	linkRepository := repositories.NewLinkRepository(db, generator)
+	var clickCounts repositories.ClickCountRepository
+	mongoClient, err := configs.ConnectMongo(envs.GetEnvWithFallback("MONGO_URI", ""))
+	if err != nil {
+		log.Printf("WARN: Read model unavailable, remaining clicks will not be reported: %v", err)
+	} else {
+		clickCounts = repositories.NewClickCountRepository(mongoClient.Database(envs.GetEnvWithFallback("MONGO_DB_NAME", "links_fast_db")))
+	}
+
+	return services.NewLinkService(linkRepository, clickCounts)
+}
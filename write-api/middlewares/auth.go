@@ -0,0 +1,113 @@
+package middlewares
+
+import (
+	"errors"
+	"linkfast/shared/auth"
+	"linkfast/write-api/models"
+	"linkfast/write-api/services"
+	"linkfast/write-api/utils/consts"
+	"linkfast/write-api/utils/res"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	ApiKeyHeader = "X-API-Key"
+	apiKeyLocal  = "api_key"
+	userLocal    = "user"
+)
+
+// Authenticate accepts either an API key in the X-API-Key header or a user token in
+// "Authorization: Bearer", and keeps the caller in the context for RequireScope and the handlers.
+func Authenticate(apiKeys services.ApiKeyService, users services.UserService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		traceID, ok := c.Locals("trace_id").(string)
+		if !ok {
+			traceID = "unknown_trace"
+		}
+
+		if plain := c.Get(ApiKeyHeader); plain != "" {
+			key, err := apiKeys.Authenticate(plain)
+			if errors.Is(err, consts.ErrInvalidApiKey) {
+				return reject(c, traceID, fiber.StatusUnauthorized, "Invalid API key")
+			}
+
+			if err != nil {
+				return reject(c, traceID, fiber.StatusInternalServerError, "Error internal in server! Try again later")
+			}
+
+			c.Locals(apiKeyLocal, key)
+			return c.Next()
+		}
+
+		token, found := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !found || token == "" {
+			return reject(c, traceID, fiber.StatusUnauthorized, "API key or bearer token is required")
+		}
+
+		identity, err := users.Authenticate(token)
+		if err != nil {
+			return reject(c, traceID, fiber.StatusUnauthorized, "Invalid or expired token")
+		}
+
+		c.Locals(userLocal, &identity)
+		return c.Next()
+	}
+}
+
+// RequireScope checks the scopes of an API key. Users may manage links, their ownership being
+// checked by the handlers, but only admins hold the other scopes.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		allowed := false
+
+		if key := CurrentApiKey(c); key != nil {
+			allowed = key.HasScope(scope)
+		} else if user := CurrentUser(c); user != nil {
+			allowed = user.IsAdmin() || slices.Contains(consts.UserScopes, scope)
+		}
+
+		if !allowed {
+			traceID, ok := c.Locals("trace_id").(string)
+			if !ok {
+				traceID = "unknown_trace"
+			}
+
+			return reject(c, traceID, fiber.StatusForbidden, "Credentials lack the "+scope+" scope")
+		}
+
+		return c.Next()
+	}
+}
+
+// CurrentApiKey returns the key that authenticated the request, or nil when it was a user or
+// the route is not behind Authenticate.
+func CurrentApiKey(c *fiber.Ctx) *models.ApiKey {
+	key, _ := c.Locals(apiKeyLocal).(*models.ApiKey)
+	return key
+}
+
+// CurrentUser returns the user that authenticated the request, or nil when it was an API key
+// or the route is not behind Authenticate.
+func CurrentUser(c *fiber.Ctx) *auth.Identity {
+	user, _ := c.Locals(userLocal).(*auth.Identity)
+	return user
+}
+
+func reject(c *fiber.Ctx, traceID string, code int, message string) error {
+	return c.Status(code).JSON(
+		res.ResponseHttp[string]{
+			Timestamp: time.Now(),
+			Payload:   "",
+			Code:      code,
+			Status:    false,
+			Message:   message,
+			Version:   1,
+			TraceID:   traceID,
+			Path:      "",
+		},
+	)
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+func (ApiKey) TableName() string {
+	return "link_fast_sc.api_keys"
+}
+
+// ApiKey stores only the SHA-256 of the key; Prefix is kept in clear so a key can be
+// recognised in listings without being recoverable.
+type ApiKey struct {
+	ID        int64      `json:"id" gorm:"primaryKey;type:bigint;not null"`
+	Name      string     `json:"name" gorm:"type:varchar(64);not null"`
+	Prefix    string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	KeyHash   string     `json:"-" gorm:"type:char(64);uniqueIndex;not null"`
+	Scopes    []string   `json:"scopes" gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+func (k ApiKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+func (k ApiKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -7,9 +7,29 @@ func (Links) TableName() string {
 }
 
 type Links struct {
-	ID         int64      `json:"id" gorm:"primaryKey;type:bigint;not null"`
-	SHORT_CODE string     `json:"short_code" gorm:"type:varchar(12);uniqueIndex;not null"`
-	LONG_URL   string     `json:"long_url" gorm:"type:text;not null"`
-	CreatedAt  time.Time  `json:"created_at"`
-	ExpiresAt  *time.Time `json:"expires_at"`
+	ID             int64             `json:"id" gorm:"primaryKey;type:bigint;not null"`
+	SHORT_CODE     string            `json:"short_code" gorm:"type:varchar(32);uniqueIndex;not null"`
+	LONG_URL       string            `json:"long_url" gorm:"type:text;not null"`
+	CreatedAt      time.Time         `json:"created_at"`
+	ExpiresAt      *time.Time        `json:"expires_at"`
+	ActivatesAt    *time.Time        `json:"activates_at"`
+	RedirectType   int               `json:"redirect_type" gorm:"type:smallint;not null;default:307"`
+	PasswordHash   *string           `json:"-" gorm:"type:text"`
+	MaxClicks      *int              `json:"max_clicks" gorm:"type:integer"`
+	GeoTargets     map[string]string `json:"geo_targets" gorm:"type:jsonb;serializer:json"`
+	DeviceTargets  map[string]string `json:"device_targets" gorm:"type:jsonb;serializer:json"`
+	Variants       []LinkVariant     `json:"variants" gorm:"type:jsonb;serializer:json"`
+	StickyVariants bool              `json:"sticky_variants" gorm:"not null;default:false"`
+	CreatedByKeyID *int64            `json:"created_by_key_id" gorm:"type:bigint;index"`
+	OwnerID        *int64            `json:"owner_id" gorm:"type:bigint;index"`
+}
+
+type LinkVariant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+func (l Links) PasswordProtected() bool {
+	return l.PasswordHash != nil && *l.PasswordHash != ""
 }
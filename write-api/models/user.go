@@ -0,0 +1,22 @@
+package models
+
+import (
+	"linkfast/write-api/utils/consts"
+	"time"
+)
+
+func (User) TableName() string {
+	return "link_fast_sc.users"
+}
+
+type User struct {
+	ID           int64     `json:"id" gorm:"primaryKey;type:bigint;not null"`
+	Email        string    `json:"email" gorm:"type:varchar(254);uniqueIndex;not null"`
+	PasswordHash string    `json:"-" gorm:"type:text;not null"`
+	Role         string    `json:"role" gorm:"type:varchar(16);not null;default:user"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == consts.RoleAdmin
+}
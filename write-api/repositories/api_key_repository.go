@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"errors"
+	"linkfast/write-api/models"
+	"linkfast/write-api/utils/consts"
+	"log"
+	"time"
+
+	"github.com/godruoyi/go-snowflake"
+	"gorm.io/gorm"
+)
+
+type ApiKeyRepository interface {
+	Create(key models.ApiKey) (*models.ApiKey, error)
+	GetByID(id int64) (models.ApiKey, error)
+	GetByHash(hash string) (models.ApiKey, error)
+	List() ([]models.ApiKey, error)
+	Revoke(key *models.ApiKey) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewApiKeyRepository(db *gorm.DB) ApiKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (a *apiKeyRepository) Create(key models.ApiKey) (*models.ApiKey, error) {
+	key.ID = int64(snowflake.ID())
+
+	result := a.db.Create(&key)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return nil, consts.ErrConflict
+	}
+
+	if result.Error != nil {
+		log.Printf("Error the create the api key: %v", result.Error)
+		return nil, consts.ErrInternal
+	}
+
+	return &key, nil
+}
+
+func (a *apiKeyRepository) GetByID(id int64) (models.ApiKey, error) {
+	key := models.ApiKey{}
+
+	if err := a.db.First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return key, consts.ErrRecordNotFound
+		}
+
+		return key, consts.ErrInternal
+	}
+
+	return key, nil
+}
+
+func (a *apiKeyRepository) GetByHash(hash string) (models.ApiKey, error) {
+	key := models.ApiKey{}
+
+	if err := a.db.Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return key, consts.ErrRecordNotFound
+		}
+
+		log.Printf("Error reading the api key: %v", err)
+		return key, consts.ErrInternal
+	}
+
+	return key, nil
+}
+
+func (a *apiKeyRepository) List() ([]models.ApiKey, error) {
+	keys := []models.ApiKey{}
+
+	if err := a.db.Order("id").Find(&keys).Error; err != nil {
+		log.Printf("Error listing the api keys: %v", err)
+		return nil, consts.ErrInternal
+	}
+
+	return keys, nil
+}
+
+func (a *apiKeyRepository) Revoke(key *models.ApiKey) error {
+	now := time.Now()
+
+	if err := a.db.Model(key).Update("revoked_at", now).Error; err != nil {
+		log.Printf("Error revoking the api key %d: %v", key.ID, err)
+		return consts.ErrInternal
+	}
+
+	key.RevokedAt = &now
+	return nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"linkfast/write-api/utils/consts"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ClickCountRepository interface {
+	GetClickCount(id int64) (int64, error)
+}
+
+type clickCountRepository struct {
+	collection *mongo.Collection
+}
+
+func NewClickCountRepository(db *mongo.Database) ClickCountRepository {
+	return &clickCountRepository{
+		collection: db.Collection("links"),
+	}
+}
+
+// GetClickCount reads the counter read-api increments on each redirect. A link that is not
+// projected yet cannot have been clicked, so it counts as zero.
+func (r *clickCountRepository) GetClickCount(id int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var doc struct {
+		ClickCount int64 `bson:"click_count"`
+	}
+
+	opts := options.FindOne().SetProjection(bson.M{"click_count": 1})
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+
+	if err != nil {
+		log.Printf("Error reading the click count of link %d: %v", id, err)
+		return 0, consts.ErrInternalDB
+	}
+
+	return doc.ClickCount, nil
+}
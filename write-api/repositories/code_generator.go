@@ -0,0 +1,281 @@
+package repositories
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"linkfast/write-api/utils/consts"
+	"log"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	StrategyBase64 = "base64"
+	StrategyBase62 = "base62"
+	StrategyRandom = "random"
+	StrategyHashid = "hashid"
+)
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Odd prime that is not a multiple of 31, so it is invertible modulo any power of 62.
+const hashidMultiplier = 2654435761
+
+type ShortCodeExists func(code string) (bool, error)
+
+type Sequence func() (int64, error)
+
+type CodeGenerator interface {
+	Generate(id int64, exists ShortCodeExists) (string, error)
+}
+
+type CodeGeneratorConfig struct {
+	Strategy   string
+	Length     int
+	MaxRetries int
+	Salt       string
+	Sequence   Sequence
+}
+
+func NewCodeGenerator(cfg CodeGeneratorConfig) (CodeGenerator, error) {
+	if cfg.Length < 4 || cfg.Length > 32 {
+		return nil, fmt.Errorf("short code length must be between 4 and 32, got %d", cfg.Length)
+	}
+
+	if cfg.MaxRetries < 1 {
+		cfg.MaxRetries = 1
+	}
+
+	switch cfg.Strategy {
+	case StrategyBase64:
+		return &base64Generator{}, nil
+	case StrategyBase62:
+		return &base62Generator{}, nil
+	case StrategyRandom:
+		return &randomGenerator{length: cfg.Length, maxRetries: cfg.MaxRetries}, nil
+	case StrategyHashid:
+		if cfg.Sequence == nil {
+			return nil, fmt.Errorf("strategy %s requires a sequence", StrategyHashid)
+		}
+
+		return &hashidGenerator{
+			alphabet:   shuffleAlphabet(base62Alphabet, cfg.Salt),
+			offset:     saltOffset(cfg.Salt),
+			minLength:  cfg.Length,
+			maxRetries: cfg.MaxRetries,
+			sequence:   cfg.Sequence,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown short code strategy: %s", cfg.Strategy)
+	}
+}
+
+func NewPostgresSequence(db *gorm.DB) Sequence {
+	return func() (int64, error) {
+		var next int64
+
+		if err := db.Raw("SELECT nextval('link_fast_sc.short_code_seq')").Scan(&next).Error; err != nil {
+			log.Printf("Error the get next value of short_code_seq: %v", err)
+			return 0, consts.ErrInternalDB
+		}
+
+		return next, nil
+	}
+}
+
+type base64Generator struct{}
+
+func (g *base64Generator) Generate(id int64, _ ShortCodeExists) (string, error) {
+	return parseToBase64(id)
+}
+
+// base62Generator encodes the snowflake id itself, so its codes are not bound by the configured
+// length: current ids take 11 characters. Use the hashid strategy for short codes.
+type base62Generator struct{}
+
+func (g *base62Generator) Generate(id int64, _ ShortCodeExists) (string, error) {
+	return encodeBase62(uint64(id), base62Alphabet, 0), nil
+}
+
+type randomGenerator struct {
+	length     int
+	maxRetries int
+}
+
+func (g *randomGenerator) Generate(_ int64, exists ShortCodeExists) (string, error) {
+	for attempt := 0; attempt < g.maxRetries; attempt++ {
+		code, err := randomCode(g.length)
+		if err != nil {
+			return "", consts.ErrInternal
+		}
+
+		taken, err := exists(code)
+		if err != nil {
+			return "", err
+		}
+
+		if !taken {
+			return code, nil
+		}
+
+		log.Printf("Short code collision on attempt %d/%d", attempt+1, g.maxRetries)
+	}
+
+	return "", consts.ErrShortCodeExhausted
+}
+
+type hashidGenerator struct {
+	alphabet   string
+	offset     uint64
+	minLength  int
+	maxRetries int
+	sequence   Sequence
+}
+
+func (g *hashidGenerator) Generate(_ int64, exists ShortCodeExists) (string, error) {
+	for attempt := 0; attempt < g.maxRetries; attempt++ {
+		next, err := g.sequence()
+		if err != nil {
+			return "", err
+		}
+
+		code := g.encode(uint64(next))
+
+		taken, err := exists(code)
+		if err != nil {
+			return "", err
+		}
+
+		if !taken {
+			return code, nil
+		}
+
+		log.Printf("Short code collision on attempt %d/%d", attempt+1, g.maxRetries)
+	}
+
+	return "", consts.ErrShortCodeExhausted
+}
+
+// encode scrambles n with an affine bijection over [0, 62^length) so that
+// sequential values produce unrelated codes of the same length.
+func (g *hashidGenerator) encode(n uint64) string {
+	length := max(g.minLength, base62Digits(n))
+
+	space, ok := pow62(length)
+	if !ok {
+		return encodeBase62(n, g.alphabet, length)
+	}
+
+	hi, lo := bits.Mul64(n, hashidMultiplier)
+	scrambled := (bits.Rem64(hi, lo, space) + g.offset%space) % space
+
+	return encodeBase62(scrambled, g.alphabet, length)
+}
+
+func encodeBase62(n uint64, alphabet string, length int) string {
+	buf := []byte{}
+	for n > 0 {
+		buf = append(buf, alphabet[n%62])
+		n /= 62
+	}
+
+	for len(buf) < max(length, 1) {
+		buf = append(buf, alphabet[0])
+	}
+
+	slices.Reverse(buf)
+	return string(buf)
+}
+
+func base62Digits(n uint64) int {
+	digits := 1
+	for n >= 62 {
+		n /= 62
+		digits++
+	}
+	return digits
+}
+
+func pow62(exp int) (uint64, bool) {
+	result := uint64(1)
+	for i := 0; i < exp; i++ {
+		hi, lo := bits.Mul64(result, 62)
+		if hi != 0 {
+			return 0, false
+		}
+		result = lo
+	}
+	return result, true
+}
+
+func randomCode(length int) (string, error) {
+	code := make([]byte, 0, length)
+	buf := make([]byte, length*2)
+
+	for len(code) < length {
+		if _, err := rand.Read(buf); err != nil {
+			log.Printf("Error the read random bytes: %v", err)
+			return "", err
+		}
+
+		for _, b := range buf {
+			// 248 is the largest multiple of 62 below 256, which keeps the distribution uniform.
+			if b >= 248 {
+				continue
+			}
+
+			code = append(code, base62Alphabet[b%62])
+			if len(code) == length {
+				break
+			}
+		}
+	}
+
+	return string(code), nil
+}
+
+func shuffleAlphabet(alphabet, salt string) string {
+	if salt == "" {
+		return alphabet
+	}
+
+	out := []byte(alphabet)
+	for i, v, p := len(out)-1, 0, 0; i > 0; i-- {
+		v %= len(salt)
+		n := int(salt[v])
+		p += n
+		j := (n + v + p) % i
+		out[i], out[j] = out[j], out[i]
+		v++
+	}
+
+	return string(out)
+}
+
+func saltOffset(salt string) uint64 {
+	var offset uint64 = 14695981039346656037
+	for i := 0; i < len(salt); i++ {
+		offset ^= uint64(salt[i])
+		offset *= 1099511628211
+	}
+	return offset
+}
+
+func parseToBase64(id int64) (string, error) {
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.BigEndian, id)
+	if err != nil {
+		log.Fatalf("Error the to write int64 to bytes: %v", err)
+		return "", consts.ErrInternal
+	}
+
+	encodedID := base64.RawURLEncoding.EncodeToString(buf.Bytes())
+
+	return strings.ReplaceAll(encodedID, "=", ""), nil
+}
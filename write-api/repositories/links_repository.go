@@ -1,14 +1,12 @@
 package repositories
 
 import (
-	"bytes"
-	"encoding/base64"
-	"encoding/binary"
 	"errors"
+	"fmt"
 	"linkfast/write-api/models"
 	"linkfast/write-api/utils/consts"
 	"log"
-	"strings"
+	"slices"
 
 	"github.com/godruoyi/go-snowflake"
 	"gorm.io/gorm"
@@ -26,35 +24,84 @@ type LinkRepository interface {
 	ExistsByShotCode(code string) (bool, error)
 	Delete(link *models.Links) error
 	ExistsByID(id int64) (bool, error)
+	Update(link *models.Links) (*models.Links, error)
+	CreateBatch(links []models.Links, atomic bool) ([]error, error)
+	ExistingShortCodes(codes []string) (map[string]bool, error)
+	ListAfterID(afterID int64, limit int) ([]models.Links, error)
 }
 
 type linkRepository struct {
-	db *gorm.DB
+	db        *gorm.DB
+	generator CodeGenerator
 }
 
-func NewLinkRepository(db *gorm.DB) LinkRepository {
+func NewLinkRepository(db *gorm.DB, generator CodeGenerator) LinkRepository {
 	return &linkRepository{
-		db: db,
+		db:        db,
+		generator: generator,
 	}
 }
 
-func (l *linkRepository) Create(link models.Links) (*models.Links, error) {
+// A generated short code can still be taken between the generator's check and the insert, so
+// a link without an alias is inserted up to this many times with a fresh code.
+const maxGeneratedCodeInserts = 3
+
+func (l *linkRepository) prepare(link *models.Links) error {
+	return l.prepareIn(l.db, link)
+}
+
+// prepareIn assigns the id and, when the link has no alias, generates its short code checking
+// collisions through db, so it can run inside an open transaction.
+func (l *linkRepository) prepareIn(db *gorm.DB, link *models.Links) error {
 	link.ID = int64(snowflake.ID())
 
-	base, err := parseToBase64(link.ID)
-	if err != nil {
-		return nil, consts.ErrInternal
+	if link.SHORT_CODE == "" {
+		code, err := l.generator.Generate(link.ID, func(code string) (bool, error) {
+			return existsShortCode(db, code)
+		})
+		if err != nil {
+			return err
+		}
+
+		link.SHORT_CODE = code
 	}
 
-	link.SHORT_CODE = base
+	return nil
+}
+
+func (l *linkRepository) Create(link models.Links) (*models.Links, error) {
+	generated := link.SHORT_CODE == ""
 
-	var err_db *gorm.DB = l.db.Create(&link)
-	if err_db.Error != nil {
-		log.Printf("Error the create the link: %v", err_db.Error)
-		return nil, consts.ErrInternal
-	}
+	for attempt := 1; ; attempt++ {
+		if generated {
+			link.SHORT_CODE = ""
+		}
 
-	return &link, nil
+		if err := l.prepare(&link); err != nil {
+			return nil, err
+		}
+
+		var err_db *gorm.DB = l.db.Create(&link)
+		if errors.Is(err_db.Error, gorm.ErrDuplicatedKey) {
+			if !generated {
+				return nil, consts.ErrConflict
+			}
+
+			log.Printf("Generated short code %s was taken on insert %d/%d", link.SHORT_CODE, attempt, maxGeneratedCodeInserts)
+			if attempt < maxGeneratedCodeInserts {
+				continue
+			}
+
+			return nil, consts.ErrShortCodeExhausted
+		}
+
+		if err_db.Error != nil {
+			log.Printf("Error the create the link: %v", err_db.Error)
+			return nil, consts.ErrInternal
+		}
+
+		return &link, nil
+	}
 }
 
 func (l *linkRepository) GetByID(id int64) (models.Links, error) {
@@ -75,11 +122,11 @@ func (l *linkRepository) GetByID(id int64) (models.Links, error) {
 func (l *linkRepository) ExistsByID(id int64) (bool, error) {
 	var count int64
 
-	result := l.db.Where(&models.Links{ID: id}).Count(&count)
+	result := l.db.Model(&models.Links{}).Where("id = ?", id).Count(&count)
 
 	if result.Error != nil {
-		log.Fatalf("error counting records: %v", result.Error)
-		return false, consts.ErrInternal
+		log.Printf("Error counting links with id %d: %v", id, result.Error)
+		return false, consts.ErrInternalDB
 	}
 
 	return count > 0, nil
@@ -103,12 +150,16 @@ func (l *linkRepository) GetByShotCode(code string) (*models.Links, error) {
 }
 
 func (l *linkRepository) ExistsByShotCode(code string) (bool, error) {
+	return existsShortCode(l.db, code)
+}
+
+func existsShortCode(db *gorm.DB, code string) (bool, error) {
 	var count int64
 
-	result := l.db.Model(&models.Links{}).Where("short_code = ?", code).Count(&count)
+	result := db.Model(&models.Links{}).Where("short_code = ?", code).Count(&count)
 
 	if result.Error != nil {
-		log.Fatalf("error counting records: %v", result.Error)
+		log.Printf("Error counting links with short code %s: %v", code, result.Error)
 		return false, consts.ErrInternalDB
 	}
 
@@ -119,7 +170,7 @@ func (l *linkRepository) Delete(link *models.Links) error {
 	result := l.db.Delete(link)
 
 	if result.Error != nil {
-		log.Fatalf("Error the delete link %v", result.Error.Error())
+		log.Printf("Error the delete link %d: %v", link.ID, result.Error)
 		return consts.ErrInternal
 	}
 
@@ -130,15 +181,172 @@ func (l *linkRepository) Delete(link *models.Links) error {
 	return nil
 }
 
-func parseToBase64(id int64) (string, error) {
-	buf := new(bytes.Buffer)
-	err := binary.Write(buf, binary.BigEndian, id)
+func (l *linkRepository) Update(link *models.Links) (*models.Links, error) {
+	result := l.db.Model(link).Select("long_url", "expires_at", "activates_at", "redirect_type", "password_hash", "geo_targets", "device_targets", "variants", "sticky_variants").Updates(link)
+
+	if result.Error != nil {
+		log.Printf("Error the update link %d: %v", link.ID, result.Error)
+		return nil, consts.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, consts.ErrRecordNotFound
+	}
+
+	return link, nil
+}
+
+// CreateBatch inserts all links in one transaction. An atomic batch is all or nothing and any
+// failure is returned as the second value; otherwise every link runs under its own savepoint
+// and its failure is reported at the same index of the first value. Links without an alias
+// whose generated code is taken on insert are retried with a fresh code, the whole batch at a
+// time when it is atomic.
+func (l *linkRepository) CreateBatch(links []models.Links, atomic bool) ([]error, error) {
+	generated := make([]bool, len(links))
+	for i := range links {
+		generated[i] = links[i].SHORT_CODE == ""
+	}
+
+	if atomic {
+		return make([]error, len(links)), l.createAtomic(links, generated)
+	}
+
+	return l.createPartial(links, generated)
+}
+
+func (l *linkRepository) createAtomic(links []models.Links, generated []bool) error {
+	for attempt := 1; ; attempt++ {
+		// Short codes are generated before the transaction opens, since the generator checks
+		// for collisions through its own connection.
+		for i := range links {
+			if generated[i] {
+				links[i].SHORT_CODE = ""
+			}
+
+			if err := l.prepare(&links[i]); err != nil {
+				return err
+			}
+		}
+
+		err := l.db.Transaction(func(tx *gorm.DB) error {
+			return tx.CreateInBatches(&links, 500).Error
+		})
+		if err == nil {
+			return nil
+		}
+
+		if errors.Is(err, gorm.ErrDuplicatedKey) && slices.Contains(generated, true) && attempt < maxGeneratedCodeInserts {
+			log.Printf("Batch of %d links hit a taken short code on insert %d/%d", len(links), attempt, maxGeneratedCodeInserts)
+			continue
+		}
+
+		log.Printf("Error creating a batch of %d links: %v", len(links), err)
+		return translateCreateError(err)
+	}
+}
+
+func (l *linkRepository) createPartial(links []models.Links, generated []bool) ([]error, error) {
+	itemErrors := make([]error, len(links))
+
+	for i := range links {
+		if err := l.prepare(&links[i]); err != nil {
+			itemErrors[i] = err
+		}
+	}
+
+	err := l.db.Transaction(func(tx *gorm.DB) error {
+		for i := range links {
+			if itemErrors[i] != nil {
+				continue
+			}
+
+			savepoint := fmt.Sprintf("link_%d", i)
+
+			for attempt := 1; ; attempt++ {
+				if err := tx.SavePoint(savepoint).Error; err != nil {
+					return err
+				}
+
+				err := tx.Create(&links[i]).Error
+				if err == nil {
+					break
+				}
+
+				if err := tx.RollbackTo(savepoint).Error; err != nil {
+					return err
+				}
+
+				if !generated[i] || !errors.Is(err, gorm.ErrDuplicatedKey) {
+					itemErrors[i] = translateCreateError(err)
+					break
+				}
+
+				log.Printf("Generated short code %s was taken on insert %d/%d", links[i].SHORT_CODE, attempt, maxGeneratedCodeInserts)
+				if attempt == maxGeneratedCodeInserts {
+					itemErrors[i] = consts.ErrShortCodeExhausted
+					break
+				}
+
+				links[i].SHORT_CODE = ""
+				if err := l.prepareIn(tx, &links[i]); err != nil {
+					itemErrors[i] = err
+					break
+				}
+			}
+		}
+
+		return nil
+	})
+
 	if err != nil {
-		log.Fatalf("Error the to write int64 to bytes: %v", err)
-		return "", consts.ErrInternal
+		log.Printf("Error creating a batch of %d links: %v", len(links), err)
+		return itemErrors, translateCreateError(err)
+	}
+
+	return itemErrors, nil
+}
+
+func (l *linkRepository) ExistingShortCodes(codes []string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	if len(codes) == 0 {
+		return existing, nil
 	}
 
-	encodedID := base64.RawURLEncoding.EncodeToString(buf.Bytes())
+	var found []string
+	result := l.db.Model(&models.Links{}).Where("short_code IN ?", codes).Pluck("short_code", &found)
+	if result.Error != nil {
+		log.Printf("Error checking existing short codes: %v", result.Error)
+		return nil, consts.ErrInternalDB
+	}
 
-	return strings.ReplaceAll(encodedID, "=", ""), nil
+	for _, code := range found {
+		existing[code] = true
+	}
+
+	return existing, nil
+}
+
+// ListAfterID pages through the table by primary key, so each page is an index range scan
+// no matter how deep the iteration goes.
+func (l *linkRepository) ListAfterID(afterID int64, limit int) ([]models.Links, error) {
+	links := []models.Links{}
+
+	result := l.db.Where("id > ?", afterID).Order("id").Limit(limit).Find(&links)
+	if result.Error != nil {
+		log.Printf("Error listing links after id %d: %v", afterID, result.Error)
+		return nil, consts.ErrInternalDB
+	}
+
+	return links, nil
+}
+
+func translateCreateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return consts.ErrConflict
+	case errors.Is(err, consts.ErrShortCodeExhausted):
+		return err
+	default:
+		return consts.ErrInternal
+	}
 }
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"errors"
+	"linkfast/write-api/models"
+	"linkfast/write-api/utils/consts"
+	"log"
+
+	"github.com/godruoyi/go-snowflake"
+	"gorm.io/gorm"
+)
+
+type UserRepository interface {
+	Create(user models.User) (*models.User, error)
+	GetByEmail(email string) (models.User, error)
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (u *userRepository) Create(user models.User) (*models.User, error) {
+	user.ID = int64(snowflake.ID())
+
+	result := u.db.Create(&user)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return nil, consts.ErrConflict
+	}
+
+	if result.Error != nil {
+		log.Printf("Error the create the user: %v", result.Error)
+		return nil, consts.ErrInternal
+	}
+
+	return &user, nil
+}
+
+func (u *userRepository) GetByEmail(email string) (models.User, error) {
+	user := models.User{}
+
+	if err := u.db.Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, consts.ErrRecordNotFound
+		}
+
+		log.Printf("Error reading the user: %v", err)
+		return user, consts.ErrInternal
+	}
+
+	return user, nil
+}
@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"linkfast/write-api/handlers"
+	"linkfast/write-api/middlewares"
+	"linkfast/write-api/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ApiKeyRoute(app *fiber.App, apiKeyHandler handlers.ApiKeyHandler) {
+	router := app.Group("/api/v1/keys", middlewares.RequireScope(consts.ScopeKeysAdmin))
+
+	router.Get("", apiKeyHandler.List)
+	router.Post("", apiKeyHandler.Create)
+	router.Delete("/:id", apiKeyHandler.Revoke)
+}
@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"linkfast/write-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthRoute must be registered before the authentication middleware, as these are the
+// routes used to get credentials in the first place.
+func AuthRoute(app *fiber.App, userHandler handlers.UserHandler) {
+	router := app.Group("/api/v1/auth")
+
+	router.Post("/register", userHandler.Register)
+	router.Post("/login", userHandler.Login)
+}
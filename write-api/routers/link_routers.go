@@ -2,6 +2,8 @@ package routers
 
 import (
 	"linkfast/write-api/handlers"
+	"linkfast/write-api/middlewares"
+	"linkfast/write-api/utils/consts"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -9,8 +11,16 @@ import (
 func LinkRoute(app *fiber.App, linkHandler handlers.LinkHandler) {
 	router := app.Group("/api/v1/links")
 
-	router.Get("/:id", linkHandler.GetByID)
-	router.Get("/:code/code", linkHandler.GetByShotCode)
-	router.Post("", linkHandler.Create)
-	router.Delete("/:id", linkHandler.Delete)
+	read := middlewares.RequireScope(consts.ScopeLinksRead)
+	write := middlewares.RequireScope(consts.ScopeLinksWrite)
+	remove := middlewares.RequireScope(consts.ScopeLinksDelete)
+
+	router.Get("/export", read, linkHandler.Export)
+	router.Get("/:id", read, linkHandler.GetByID)
+	router.Get("/:code/code", read, linkHandler.GetByShotCode)
+	router.Post("", write, linkHandler.Create)
+	router.Post("/batch", write, linkHandler.CreateBatch)
+	router.Post("/import", write, linkHandler.Import)
+	router.Patch("/:id", write, linkHandler.Update)
+	router.Delete("/:id", remove, linkHandler.Delete)
 }
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"linkfast/shared/auth"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/models"
+	"linkfast/write-api/repositories"
+	"linkfast/write-api/utils/consts"
+	"log"
+)
+
+const (
+	apiKeyPrefix       = "lf_"
+	apiKeyVisibleChars = 8
+	minBootstrapLength = 32
+)
+
+type ApiKeyService interface {
+	Issue(dto dtos.CreateApiKeyDto) (*models.ApiKey, string, error)
+	Authenticate(key string) (*models.ApiKey, error)
+	List() ([]models.ApiKey, error)
+	Revoke(id int64) (*models.ApiKey, error)
+	Bootstrap(key string) error
+}
+
+type apiKeyService struct {
+	repo repositories.ApiKeyRepository
+}
+
+func NewApiKeyService(repo repositories.ApiKeyRepository) ApiKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+// Issue returns the stored key together with the plain key, which is not kept anywhere.
+func (a *apiKeyService) Issue(dto dtos.CreateApiKeyDto) (*models.ApiKey, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+
+	plain := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key, err := a.repo.Create(newApiKey(dto.Name, plain, dto.Scopes))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, plain, nil
+}
+
+func (a *apiKeyService) Authenticate(key string) (*models.ApiKey, error) {
+	stored, err := a.repo.GetByHash(auth.HashApiKey(key))
+	if errors.Is(err, consts.ErrRecordNotFound) {
+		return nil, consts.ErrInvalidApiKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.IsRevoked() {
+		return nil, consts.ErrInvalidApiKey
+	}
+
+	return &stored, nil
+}
+
+func (a *apiKeyService) List() ([]models.ApiKey, error) {
+	return a.repo.List()
+}
+
+// Revoke is idempotent: revoking a key twice keeps the first revocation time.
+func (a *apiKeyService) Revoke(id int64) (*models.ApiKey, error) {
+	key, err := a.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.IsRevoked() {
+		return &key, nil
+	}
+
+	if err := a.repo.Revoke(&key); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// Bootstrap registers a key given by the operator with every scope, so the first keys can be
+// issued on a fresh database. It does nothing when the key is already registered, even revoked.
+func (a *apiKeyService) Bootstrap(key string) error {
+	if len(key) < minBootstrapLength {
+		return consts.ErrWeakBootstrap
+	}
+
+	_, err := a.repo.GetByHash(auth.HashApiKey(key))
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, consts.ErrRecordNotFound) {
+		return err
+	}
+
+	if _, err := a.repo.Create(newApiKey("bootstrap", key, consts.ApiKeyScopes)); err != nil {
+		return err
+	}
+
+	log.Print("Bootstrap api key registered")
+	return nil
+}
+
+func newApiKey(name, plain string, scopes []string) models.ApiKey {
+	return models.ApiKey{
+		Name:    name,
+		Prefix:  plain[:min(len(plain), len(apiKeyPrefix)+apiKeyVisibleChars)],
+		KeyHash: auth.HashApiKey(plain),
+		Scopes:  scopes,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 
 	"github.com/jinzhu/copier"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type LinkService interface {
@@ -17,19 +18,115 @@ type LinkService interface {
 	GetByShotCode(code string) (*models.Links, error)
 	ExistsByShotCode(code string) (bool, error)
 	Delete(link *models.Links) error
+	Update(link *models.Links, dto dtos.UpdateLinkDto) (*models.Links, error)
+	RemainingClicks(link models.Links) (*int, error)
+	CreateBatch(items []dtos.CreateLinkDto, atomic bool) ([]BatchItem, error)
+	ListAfterID(afterID int64, limit int) ([]models.Links, error)
+}
+
+// BatchItem is the outcome of one link of a batch: the created link, or why it was not created.
+type BatchItem struct {
+	Link *models.Links
+	Err  error
 }
 
 type linkService struct {
-	repo repositories.LinkRepository
+	repo        repositories.LinkRepository
+	clickCounts repositories.ClickCountRepository
 }
 
-func NewLinkService(repo repositories.LinkRepository) LinkService {
+// NewLinkService accepts a nil clickCounts when the read model is not configured; remaining
+// clicks are then simply not reported.
+func NewLinkService(repo repositories.LinkRepository, clickCounts repositories.ClickCountRepository) LinkService {
 	return &linkService{
-		repo: repo,
+		repo:        repo,
+		clickCounts: clickCounts,
 	}
 }
 
 func (l *linkService) Create(dto dtos.CreateLinkDto) (*models.Links, error) {
+	if dto.Alias != nil {
+		exists, err := l.repo.ExistsByShotCode(*dto.Alias)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			return nil, consts.ErrConflict
+		}
+	}
+
+	link, err := buildLink(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.repo.Create(*link)
+}
+
+// CreateBatch checks every alias with a single query, including duplicates inside the batch.
+// An atomic batch with any failing item is rejected as a whole before touching the database.
+func (l *linkService) CreateBatch(items []dtos.CreateLinkDto, atomic bool) ([]BatchItem, error) {
+	results := make([]BatchItem, len(items))
+
+	aliases := []string{}
+	for _, item := range items {
+		if item.Alias != nil {
+			aliases = append(aliases, *item.Alias)
+		}
+	}
+
+	taken, err := l.repo.ExistingShortCodes(aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]models.Links, 0, len(items))
+	positions := make([]int, 0, len(items))
+	failed := false
+
+	for i, item := range items {
+		if item.Alias != nil {
+			if taken[*item.Alias] {
+				results[i].Err = consts.ErrConflict
+				failed = true
+				continue
+			}
+			taken[*item.Alias] = true
+		}
+
+		link, err := buildLink(item)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+
+		links = append(links, *link)
+		positions = append(positions, i)
+	}
+
+	if atomic && failed {
+		return results, consts.ErrBatchRejected
+	}
+
+	itemErrors, err := l.repo.CreateBatch(links, atomic)
+	if err != nil {
+		return results, err
+	}
+
+	for j, i := range positions {
+		if itemErrors[j] != nil {
+			results[i].Err = itemErrors[j]
+			continue
+		}
+		results[i].Link = &links[j]
+	}
+
+	return results, nil
+}
+
+func buildLink(dto dtos.CreateLinkDto) (*models.Links, error) {
 	link := new(models.Links)
 
 	if err := copier.Copy(link, dto); err != nil {
@@ -37,13 +134,39 @@ func (l *linkService) Create(dto dtos.CreateLinkDto) (*models.Links, error) {
 		return nil, consts.ErrInternal
 	}
 
-	return l.repo.Create(*link)
+	if dto.Alias != nil {
+		link.SHORT_CODE = *dto.Alias
+	}
+
+	if !hasValidWindow(link) {
+		return nil, consts.ErrInvalidWindow
+	}
+
+	if link.RedirectType == 0 {
+		link.RedirectType = consts.DefaultRedirectType
+	}
+
+	if dto.Password != nil {
+		hash, err := hashPassword(*dto.Password)
+		if err != nil {
+			return nil, err
+		}
+		link.PasswordHash = hash
+	} else {
+		link.PasswordHash = dto.PasswordHash
+	}
+
+	return link, nil
 }
 
 func (l *linkService) GetByID(id int64) (models.Links, error) {
 	return l.repo.GetByID(id)
 }
 
+func (l *linkService) ListAfterID(afterID int64, limit int) ([]models.Links, error) {
+	return l.repo.ListAfterID(afterID, limit)
+}
+
 func (l *linkService) ExistsByID(id int64) (bool, error) {
 	return l.repo.ExistsByID(id)
 }
@@ -59,3 +182,104 @@ func (l *linkService) ExistsByShotCode(code string) (bool, error) {
 func (l *linkService) Delete(link *models.Links) error {
 	return l.repo.Delete(link)
 }
+
+func (l *linkService) Update(link *models.Links, dto dtos.UpdateLinkDto) (*models.Links, error) {
+	if dto.LONG_URL != nil {
+		link.LONG_URL = *dto.LONG_URL
+	}
+
+	if dto.ExpiresAt != nil {
+		link.ExpiresAt = dto.ExpiresAt
+	}
+
+	if dto.ClearExpiresAt {
+		link.ExpiresAt = nil
+	}
+
+	if dto.ActivatesAt != nil {
+		link.ActivatesAt = dto.ActivatesAt
+	}
+
+	if dto.ClearActivatesAt {
+		link.ActivatesAt = nil
+	}
+
+	if !hasValidWindow(link) {
+		return nil, consts.ErrInvalidWindow
+	}
+
+	if dto.RedirectType != nil {
+		link.RedirectType = *dto.RedirectType
+	}
+
+	if dto.Password != nil {
+		hash, err := hashPassword(*dto.Password)
+		if err != nil {
+			return nil, err
+		}
+		link.PasswordHash = hash
+	}
+
+	if dto.RemovePassword {
+		link.PasswordHash = nil
+	}
+
+	if dto.GeoTargets != nil {
+		link.GeoTargets = dto.GeoTargets
+		if len(dto.GeoTargets) == 0 {
+			link.GeoTargets = nil
+		}
+	}
+
+	if dto.DeviceTargets != nil {
+		link.DeviceTargets = dto.DeviceTargets
+		if len(dto.DeviceTargets) == 0 {
+			link.DeviceTargets = nil
+		}
+	}
+
+	if dto.Variants != nil {
+		link.Variants = nil
+		if len(dto.Variants) > 0 {
+			if err := copier.Copy(&link.Variants, dto.Variants); err != nil {
+				log.Printf("Error copying the variants of link %d: %v", link.ID, err)
+				return nil, consts.ErrInternal
+			}
+		}
+	}
+
+	if dto.StickyVariants != nil {
+		link.StickyVariants = *dto.StickyVariants
+	}
+
+	return l.repo.Update(link)
+}
+
+func (l *linkService) RemainingClicks(link models.Links) (*int, error) {
+	if link.MaxClicks == nil || l.clickCounts == nil {
+		return nil, nil
+	}
+
+	count, err := l.clickCounts.GetClickCount(link.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := max(0, *link.MaxClicks-int(count))
+	return &remaining, nil
+}
+
+func hasValidWindow(link *models.Links) bool {
+	return link.ActivatesAt == nil || link.ExpiresAt == nil || link.ActivatesAt.Before(*link.ExpiresAt)
+}
+
+func hashPassword(password string) (*string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Printf("Error hashing password: %v", err)
+		return nil, consts.ErrInternal
+	}
+
+	hashed := string(hash)
+	return &hashed, nil
+}
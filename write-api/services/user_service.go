@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"linkfast/shared/auth"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/models"
+	"linkfast/write-api/repositories"
+	"linkfast/write-api/utils/consts"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minBootstrapPasswordLength is stricter than registration, since the bootstrap account is
+// an admin.
+const minBootstrapPasswordLength = 12
+
+// dummyHash is compared against when the email is unknown, so a login takes as long for an
+// unknown email as for a wrong password.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("linkfast-dummy-password"), bcrypt.DefaultCost)
+
+type UserService interface {
+	Register(dto dtos.RegisterUserDto) (*models.User, error)
+	Login(dto dtos.LoginDto) (*models.User, string, time.Time, error)
+	Authenticate(token string) (auth.Identity, error)
+	Bootstrap(email, password string) error
+}
+
+type userService struct {
+	repo   repositories.UserRepository
+	tokens *auth.TokenIssuer
+}
+
+func NewUserService(repo repositories.UserRepository, tokens *auth.TokenIssuer) UserService {
+	return &userService{repo: repo, tokens: tokens}
+}
+
+func (u *userService) Register(dto dtos.RegisterUserDto) (*models.User, error) {
+	return u.create(dto.Email, dto.Password, consts.RoleUser)
+}
+
+func (u *userService) Login(dto dtos.LoginDto) (*models.User, string, time.Time, error) {
+	user, err := u.repo.GetByEmail(normalizeEmail(dto.Email))
+	if errors.Is(err, consts.ErrRecordNotFound) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(dto.Password))
+		return nil, "", time.Time{}, consts.ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(dto.Password)) != nil {
+		return nil, "", time.Time{}, consts.ErrInvalidCredentials
+	}
+
+	token, expiresAt, err := u.tokens.Issue(auth.Identity{UserID: user.ID, Role: user.Role})
+	if err != nil {
+		log.Printf("Error signing the token of user %d: %v", user.ID, err)
+		return nil, "", time.Time{}, consts.ErrInternal
+	}
+
+	return &user, token, expiresAt, nil
+}
+
+func (u *userService) Authenticate(token string) (auth.Identity, error) {
+	return u.tokens.Parse(token)
+}
+
+// Bootstrap creates the admin account given by the operator when it does not exist yet, since
+// registration only creates regular users.
+func (u *userService) Bootstrap(email, password string) error {
+	if len(password) < minBootstrapPasswordLength {
+		return consts.ErrWeakBootstrapPassword
+	}
+
+	_, err := u.repo.GetByEmail(normalizeEmail(email))
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, consts.ErrRecordNotFound) {
+		return err
+	}
+
+	if _, err := u.create(email, password, consts.RoleAdmin); err != nil {
+		return err
+	}
+
+	log.Printf("Bootstrap admin %s registered", normalizeEmail(email))
+	return nil
+}
+
+func (u *userService) create(email, password, role string) (*models.User, error) {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repo.Create(models.User{
+		Email:        normalizeEmail(email),
+		PasswordHash: *hash,
+		Role:         role,
+	})
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,316 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"gorm.io/gorm"
+
+	"linkfast/shared/auth"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/handlers"
+	"linkfast/write-api/middlewares"
+	"linkfast/write-api/models"
+	"linkfast/write-api/repositories"
+	"linkfast/write-api/routers"
+	"linkfast/write-api/services"
+	"linkfast/write-api/utils/consts"
+)
+
+const (
+	bootstrapKey  = "lf_test-bootstrap-key-with-enough-entropy"
+	adminEmail    = "admin@example.com"
+	adminPassword = "admin-password"
+)
+
+func setupAuthApp() (*fiber.App, *gorm.DB) {
+	db := openTestDB()
+
+	if err := db.AutoMigrate(&models.Links{}, &models.ApiKey{}, &models.User{}); err != nil {
+		log.Fatalf("Falha ao executar a migração de teste: %v", err)
+	}
+
+	apiKeyService := services.NewApiKeyService(repositories.NewApiKeyRepository(db))
+	if err := apiKeyService.Bootstrap(bootstrapKey); err != nil {
+		log.Fatalf("Falha ao registrar a chave de bootstrap: %v", err)
+	}
+
+	userService := services.NewUserService(repositories.NewUserRepository(db), auth.NewTokenIssuer([]byte("test-secret"), time.Hour))
+	if err := userService.Bootstrap(adminEmail, adminPassword); err != nil {
+		log.Fatalf("Falha ao registrar o admin de bootstrap: %v", err)
+	}
+
+	app := fiber.New()
+
+	app.Use(requestid.New(requestid.Config{
+		ContextKey: "trace_id",
+	}))
+
+	routers.AuthRoute(app, handlers.NewUserHandler(userService))
+	app.Use(middlewares.Authenticate(apiKeyService, userService))
+
+	routers.LinkRoute(app, handlers.NewLinkHandler(services.NewLinkService(newTestRepository(db), nil)))
+	routers.ApiKeyRoute(app, handlers.NewApiKeyHandler(apiKeyService))
+
+	return app, db
+}
+
+// sendAuth sends key in X-API-Key, or as a bearer token when it is prefixed with "Bearer ".
+func sendAuth(t *testing.T, app *fiber.App, method, path, key, body string) (int, []byte) {
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if token, ok := strings.CutPrefix(key, "Bearer "); ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if key != "" {
+		req.Header.Set(middlewares.ApiKeyHeader, key)
+	}
+
+	resp, err := app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, bodyBytes
+}
+
+func TestApiKeys_Integration(t *testing.T) {
+	app, db := setupAuthApp()
+
+	send := func(method, path, key, body string) (int, []byte) {
+		return sendAuth(t, app, method, path, key, body)
+	}
+
+	issue := func(scopes string) dtos.IssuedApiKeyDto {
+		code, body := send(http.MethodPost, "/api/v1/keys", bootstrapKey, `{"name": "deploy-bot", "scopes": `+scopes+`}`)
+		if code != http.StatusCreated {
+			t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusCreated, code, body)
+		}
+
+		var response struct {
+			Payload dtos.IssuedApiKeyDto `json:"payload"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			t.Fatalf("Falha ao decodificar a resposta JSON: %v", err)
+		}
+		return response.Payload
+	}
+
+	writer := issue(`["links:read", "links:write"]`)
+	if writer.Key == "" || writer.Prefix == "" || writer.Key[:len(writer.Prefix)] != writer.Prefix {
+		t.Fatalf("Chave emitida inválida: %+v", writer)
+	}
+
+	var stored models.ApiKey
+	db.First(&stored, writer.ID)
+	if stored.KeyHash == "" || stored.KeyHash == writer.Key {
+		t.Errorf("A chave deveria ser armazenada apenas como hash, obtido %q", stored.KeyHash)
+	}
+
+	code, body := send(http.MethodPost, "/api/v1/links", writer.Key, `{"long_url": "https://www.example.com/owned"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusCreated, code, body)
+	}
+
+	var created struct {
+		Payload dtos.LinkDto `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		t.Fatalf("Falha ao decodificar a resposta JSON: %v", err)
+	}
+
+	if created.Payload.CreatedByKeyID == nil || *created.Payload.CreatedByKeyID != writer.ID {
+		t.Errorf("created_by_key_id esperado %d, obtido %v", writer.ID, created.Payload.CreatedByKeyID)
+	}
+
+	linkPath := fmt.Sprintf("/api/v1/links/%d", created.Payload.ID)
+
+	tests := []struct {
+		description  string
+		method       string
+		path         string
+		key          string
+		body         string
+		expectedCode int
+	}{
+		{description: "Falha: Requisição sem chave", method: http.MethodGet, path: linkPath, expectedCode: http.StatusUnauthorized},
+		{description: "Falha: Chave desconhecida", method: http.MethodGet, path: linkPath, key: "lf_unknown", expectedCode: http.StatusUnauthorized},
+		{description: "Sucesso: Leitura com links:read", method: http.MethodGet, path: linkPath, key: writer.Key, expectedCode: http.StatusOK},
+		{description: "Falha: Delete sem links:delete", method: http.MethodDelete, path: linkPath, key: writer.Key, expectedCode: http.StatusForbidden},
+		{description: "Falha: Gestão de chaves sem keys:admin", method: http.MethodGet, path: "/api/v1/keys", key: writer.Key, expectedCode: http.StatusForbidden},
+		{description: "Falha: Escopo desconhecido", method: http.MethodPost, path: "/api/v1/keys", key: bootstrapKey, body: `{"name": "bad", "scopes": ["links:all"]}`, expectedCode: http.StatusBadRequest},
+		{description: "Sucesso: Listagem com keys:admin", method: http.MethodGet, path: "/api/v1/keys", key: bootstrapKey, expectedCode: http.StatusOK},
+		{description: "Sucesso: Revogação da chave", method: http.MethodDelete, path: fmt.Sprintf("/api/v1/keys/%d", writer.ID), key: bootstrapKey, expectedCode: http.StatusOK},
+		{description: "Falha: Chave revogada", method: http.MethodGet, path: linkPath, key: writer.Key, expectedCode: http.StatusUnauthorized},
+		{description: "Falha: Revogação de chave inexistente", method: http.MethodDelete, path: "/api/v1/keys/12345", key: bootstrapKey, expectedCode: http.StatusNotFound},
+		{description: "Sucesso: Delete com links:delete", method: http.MethodDelete, path: linkPath, key: bootstrapKey, expectedCode: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			code, body := send(test.method, test.path, test.key, test.body)
+			if code != test.expectedCode {
+				t.Errorf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", test.expectedCode, code, body)
+			}
+		})
+	}
+}
+
+func TestUsers_Ownership_Integration(t *testing.T) {
+	app, db := setupAuthApp()
+
+	send := func(method, path, key, body string) (int, []byte) {
+		return sendAuth(t, app, method, path, key, body)
+	}
+
+	login := func(email, password string) (string, dtos.UserDto) {
+		code, body := send(http.MethodPost, "/api/v1/auth/login", "", fmt.Sprintf(`{"email": %q, "password": %q}`, email, password))
+		if code != http.StatusOK {
+			t.Fatalf("Login de %s: esperado %d, obtido %d. Corpo da resposta: %s", email, http.StatusOK, code, body)
+		}
+
+		var response struct {
+			Payload dtos.TokenDto `json:"payload"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			t.Fatalf("Falha ao decodificar a resposta JSON: %v", err)
+		}
+		return "Bearer " + response.Payload.Token, response.Payload.User
+	}
+
+	for _, email := range []string{"alice@example.com", "bob@example.com"} {
+		if code, body := send(http.MethodPost, "/api/v1/auth/register", "", fmt.Sprintf(`{"email": %q, "password": "secret-password"}`, email)); code != http.StatusCreated {
+			t.Fatalf("Registro de %s: esperado %d, obtido %d. Corpo da resposta: %s", email, http.StatusCreated, code, body)
+		}
+	}
+
+	alice, aliceUser := login("Alice@Example.com", "secret-password")
+	bob, bobUser := login("bob@example.com", "secret-password")
+	admin, adminUser := login(adminEmail, adminPassword)
+
+	if aliceUser.Role != "user" || adminUser.Role != "admin" {
+		t.Fatalf("Papéis inesperados: alice %q, admin %q", aliceUser.Role, adminUser.Role)
+	}
+
+	code, body := send(http.MethodPost, "/api/v1/links", alice, `{"long_url": "https://www.example.com/alice"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusCreated, code, body)
+	}
+
+	var created struct {
+		Payload dtos.LinkDto `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		t.Fatalf("Falha ao decodificar a resposta JSON: %v", err)
+	}
+
+	if created.Payload.OwnerID == nil || *created.Payload.OwnerID != aliceUser.ID {
+		t.Fatalf("owner_id esperado %d, obtido %v", aliceUser.ID, created.Payload.OwnerID)
+	}
+
+	linkPath := fmt.Sprintf("/api/v1/links/%d", created.Payload.ID)
+	codePath := fmt.Sprintf("/api/v1/links/%s/code", created.Payload.SHORT_CODE)
+
+	tests := []struct {
+		description  string
+		method       string
+		path         string
+		key          string
+		body         string
+		expectedCode int
+	}{
+		{description: "Falha: Email já registrado", method: http.MethodPost, path: "/api/v1/auth/register", body: `{"email": "ALICE@example.com", "password": "another-password"}`, expectedCode: http.StatusConflict},
+		{description: "Falha: Senha incorreta", method: http.MethodPost, path: "/api/v1/auth/login", body: `{"email": "alice@example.com", "password": "wrong-password"}`, expectedCode: http.StatusUnauthorized},
+		{description: "Falha: Token inválido", method: http.MethodGet, path: linkPath, key: "Bearer not-a-token", expectedCode: http.StatusUnauthorized},
+		{description: "Sucesso: Dona lê o link", method: http.MethodGet, path: linkPath, key: alice, expectedCode: http.StatusOK},
+		{description: "Falha: Outro usuário lê o link", method: http.MethodGet, path: linkPath, key: bob, expectedCode: http.StatusForbidden},
+		{description: "Falha: Outro usuário lê o link pelo código", method: http.MethodGet, path: codePath, key: bob, expectedCode: http.StatusForbidden},
+		{description: "Falha: Outro usuário altera o link", method: http.MethodPatch, path: linkPath, key: bob, body: `{"long_url": "https://www.example.com/bob"}`, expectedCode: http.StatusForbidden},
+		{description: "Falha: Outro usuário remove o link", method: http.MethodDelete, path: linkPath, key: bob, expectedCode: http.StatusForbidden},
+		{description: "Falha: Usuário exporta todos os links", method: http.MethodGet, path: "/api/v1/links/export", key: alice, expectedCode: http.StatusForbidden},
+		{description: "Falha: Usuário gerencia chaves", method: http.MethodGet, path: "/api/v1/keys", key: alice, expectedCode: http.StatusForbidden},
+		{description: "Sucesso: Dona altera o link", method: http.MethodPatch, path: linkPath, key: alice, body: `{"long_url": "https://www.example.com/alice-2"}`, expectedCode: http.StatusOK},
+		{description: "Sucesso: Admin gerencia chaves", method: http.MethodGet, path: "/api/v1/keys", key: admin, expectedCode: http.StatusOK},
+		{description: "Sucesso: Admin remove o link", method: http.MethodDelete, path: linkPath, key: admin, expectedCode: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			code, body := send(test.method, test.path, test.key, test.body)
+			if code != test.expectedCode {
+				t.Errorf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", test.expectedCode, code, body)
+			}
+		})
+	}
+
+	// Só o admin mantém o owner_id de um arquivo importado; o usuário importa para si mesmo.
+	imports := []struct {
+		key           string
+		shortCode     string
+		expectedOwner int64
+	}{
+		{key: alice, shortCode: "alice-import", expectedOwner: aliceUser.ID},
+		{key: admin, shortCode: "admin-import", expectedOwner: bobUser.ID},
+	}
+
+	for _, imp := range imports {
+		line := fmt.Sprintf(`{"long_url": "https://www.example.com/%s", "short_code": %q, "owner_id": %d}`, imp.shortCode, imp.shortCode, bobUser.ID)
+		if code, body := send(http.MethodPost, "/api/v1/links/import", imp.key, line); code != http.StatusOK {
+			t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusOK, code, body)
+		}
+
+		var imported models.Links
+		if err := db.Where("short_code = ?", imp.shortCode).First(&imported).Error; err != nil {
+			t.Fatalf("Link %s não foi importado: %v", imp.shortCode, err)
+		}
+
+		if imported.OwnerID == nil || *imported.OwnerID != imp.expectedOwner {
+			t.Errorf("Link %s: owner_id esperado %d, obtido %v", imp.shortCode, imp.expectedOwner, imported.OwnerID)
+		}
+	}
+}
+
+func TestUsers_Bootstrap_Integration(t *testing.T) {
+	db := openTestDB()
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("Falha ao executar a migração de teste: %v", err)
+	}
+
+	repo := repositories.NewUserRepository(db)
+	userService := services.NewUserService(repo, auth.NewTokenIssuer([]byte("test-secret"), time.Hour))
+
+	tests := []struct {
+		description string
+		password    string
+		expectedErr error
+	}{
+		{description: "Senha vazia", password: "", expectedErr: consts.ErrWeakBootstrapPassword},
+		{description: "Senha curta", password: "admin-pass", expectedErr: consts.ErrWeakBootstrapPassword},
+		{description: "Senha com o tamanho mínimo", password: "admin-pass12", expectedErr: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if err := userService.Bootstrap(adminEmail, test.password); !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Erro esperado: %v, obtido: %v", test.expectedErr, err)
+			}
+
+			_, err := repo.GetByEmail(adminEmail)
+			if created := err == nil; created != (test.expectedErr == nil) {
+				t.Errorf("Admin criado: %v, erro da busca: %v", created, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"linkfast/write-api/models"
+	"linkfast/write-api/repositories"
+	"linkfast/write-api/utils/consts"
+)
+
+var base62Pattern = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+
+func counterSequence() repositories.Sequence {
+	var next int64
+	return func() (int64, error) {
+		next++
+		return next, nil
+	}
+}
+
+func TestCodeGenerator_Random_RetriesOnCollision(t *testing.T) {
+	generator, err := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy:   repositories.StrategyRandom,
+		Length:     7,
+		MaxRetries: 5,
+	})
+	if err != nil {
+		t.Fatalf("Falha ao criar o gerador: %v", err)
+	}
+
+	calls := 0
+	exists := func(code string) (bool, error) {
+		calls++
+		return calls <= 3, nil
+	}
+
+	code, err := generator.Generate(1, exists)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if calls != 4 {
+		t.Errorf("Esperado 4 verificações de existência, obtido %d", calls)
+	}
+
+	if len(code) != 7 || !base62Pattern.MatchString(code) {
+		t.Errorf("Código gerado inválido: %q", code)
+	}
+}
+
+func TestCodeGenerator_Random_ExhaustsRetries(t *testing.T) {
+	generator, err := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy:   repositories.StrategyRandom,
+		Length:     5,
+		MaxRetries: 3,
+	})
+	if err != nil {
+		t.Fatalf("Falha ao criar o gerador: %v", err)
+	}
+
+	calls := 0
+	_, err = generator.Generate(1, func(code string) (bool, error) {
+		calls++
+		return true, nil
+	})
+
+	if !errors.Is(err, consts.ErrShortCodeExhausted) {
+		t.Errorf("Esperado ErrShortCodeExhausted, obtido %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Esperado 3 tentativas, obtido %d", calls)
+	}
+}
+
+func TestCodeGenerator_Random_PropagatesLookupError(t *testing.T) {
+	generator, _ := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy:   repositories.StrategyRandom,
+		Length:     7,
+		MaxRetries: 3,
+	})
+
+	_, err := generator.Generate(1, func(code string) (bool, error) {
+		return false, consts.ErrInternalDB
+	})
+
+	if !errors.Is(err, consts.ErrInternalDB) {
+		t.Errorf("Esperado ErrInternalDB, obtido %v", err)
+	}
+}
+
+func TestCodeGenerator_Base62(t *testing.T) {
+	generator, err := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy: repositories.StrategyBase62,
+		Length:   7,
+	})
+	if err != nil {
+		t.Fatalf("Falha ao criar o gerador: %v", err)
+	}
+
+	tests := []struct {
+		id       int64
+		expected string
+	}{
+		{id: 0, expected: "0"},
+		{id: 61, expected: "z"},
+		{id: 62, expected: "10"},
+		{id: 2257190916584378368, expected: "2gjxPsS3756"},
+	}
+
+	for _, test := range tests {
+		code, err := generator.Generate(test.id, nil)
+		if err != nil {
+			t.Fatalf("Erro inesperado para id %d: %v", test.id, err)
+		}
+
+		if code != test.expected {
+			t.Errorf("Id %d: esperado %s, obtido %s", test.id, test.expected, code)
+		}
+	}
+}
+
+func TestCodeGenerator_Hashid_UniqueAndObfuscated(t *testing.T) {
+	generator, err := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy:   repositories.StrategyHashid,
+		Length:     4,
+		MaxRetries: 3,
+		Salt:       "linkfast",
+		Sequence:   counterSequence(),
+	})
+	if err != nil {
+		t.Fatalf("Falha ao criar o gerador: %v", err)
+	}
+
+	notTaken := func(code string) (bool, error) { return false, nil }
+	seen := map[string]bool{}
+	var previous string
+
+	for i := 0; i < 20000; i++ {
+		code, err := generator.Generate(0, notTaken)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if len(code) != 4 || !base62Pattern.MatchString(code) {
+			t.Fatalf("Código gerado inválido: %q", code)
+		}
+
+		if seen[code] {
+			t.Fatalf("Código duplicado gerado: %s", code)
+		}
+
+		if previous != "" && code[:3] == previous[:3] {
+			t.Errorf("Códigos sequenciais muito parecidos: %s e %s", previous, code)
+		}
+
+		seen[code] = true
+		previous = code
+	}
+}
+
+func TestCodeGenerator_Hashid_SaltChangesCodes(t *testing.T) {
+	first, _ := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy: repositories.StrategyHashid, Length: 6, Salt: "salt-a", Sequence: counterSequence(),
+	})
+	second, _ := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy: repositories.StrategyHashid, Length: 6, Salt: "salt-b", Sequence: counterSequence(),
+	})
+
+	notTaken := func(code string) (bool, error) { return false, nil }
+	a, _ := first.Generate(0, notTaken)
+	b, _ := second.Generate(0, notTaken)
+
+	if a == b {
+		t.Errorf("Salts diferentes deveriam gerar códigos diferentes: %s", a)
+	}
+}
+
+func TestCodeGenerator_Hashid_SkipsTakenCode(t *testing.T) {
+	generator, _ := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy:   repositories.StrategyHashid,
+		Length:     6,
+		MaxRetries: 3,
+		Sequence:   counterSequence(),
+	})
+
+	var checked []string
+	code, err := generator.Generate(0, func(code string) (bool, error) {
+		checked = append(checked, code)
+		return len(checked) == 1, nil
+	})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(checked) != 2 || code != checked[1] || code == checked[0] {
+		t.Errorf("Esperado pular o código ocupado %v, obtido %s", checked, code)
+	}
+}
+
+func TestCodeGenerator_InvalidConfig(t *testing.T) {
+	tests := []repositories.CodeGeneratorConfig{
+		{Strategy: "unknown", Length: 7},
+		{Strategy: repositories.StrategyRandom, Length: 2},
+		{Strategy: repositories.StrategyRandom, Length: 33},
+		{Strategy: repositories.StrategyHashid, Length: 7},
+	}
+
+	for _, cfg := range tests {
+		t.Run(fmt.Sprintf("%s/%d", cfg.Strategy, cfg.Length), func(t *testing.T) {
+			if _, err := repositories.NewCodeGenerator(cfg); err == nil {
+				t.Errorf("Esperado erro para configuração %+v", cfg)
+			}
+		})
+	}
+}
+
+// scriptedGenerator hands out fixed codes without checking them, simulating a code that is
+// taken by a concurrent insert after the generator's check.
+type scriptedGenerator struct {
+	codes []string
+	calls int
+}
+
+func (g *scriptedGenerator) Generate(_ int64, _ repositories.ShortCodeExists) (string, error) {
+	code := g.codes[min(g.calls, len(g.codes)-1)]
+	g.calls++
+	return code, nil
+}
+
+func TestLinkRepository_GeneratedCodeTakenOnInsert(t *testing.T) {
+	db := openTestDB()
+	if err := db.AutoMigrate(&models.Links{}); err != nil {
+		t.Fatalf("Falha ao executar a migração de teste: %v", err)
+	}
+
+	if _, err := newTestRepository(db).Create(models.Links{SHORT_CODE: "taken01", LONG_URL: "https://example.com/a"}); err != nil {
+		t.Fatalf("Falha ao criar o link existente: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		codes        []string
+		link         models.Links
+		expectedCode string
+		expectedErr  error
+	}{
+		{name: "regenerates", codes: []string{"taken01", "fresh01"}, expectedCode: "fresh01"},
+		{name: "exhausts", codes: []string{"taken01"}, expectedErr: consts.ErrShortCodeExhausted},
+		{name: "alias conflict", codes: []string{"fresh02"}, link: models.Links{SHORT_CODE: "taken01"}, expectedErr: consts.ErrConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			generator := &scriptedGenerator{codes: test.codes}
+			repo := repositories.NewLinkRepository(db, generator)
+
+			test.link.LONG_URL = "https://example.com/b"
+			created, err := repo.Create(test.link)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Erro esperado: %v, obtido: %v", test.expectedErr, err)
+			}
+
+			if test.expectedErr == nil && created.SHORT_CODE != test.expectedCode {
+				t.Errorf("Código esperado: %s, obtido: %s", test.expectedCode, created.SHORT_CODE)
+			}
+
+			if test.expectedErr == consts.ErrShortCodeExhausted && generator.calls != 3 {
+				t.Errorf("Esperado 3 tentativas de inserção, obtido %d", generator.calls)
+			}
+		})
+	}
+
+	t.Run("partial batch", func(t *testing.T) {
+		repo := repositories.NewLinkRepository(db, &scriptedGenerator{codes: []string{"taken01", "fresh03"}})
+
+		links := []models.Links{{LONG_URL: "https://example.com/c"}, {SHORT_CODE: "taken01", LONG_URL: "https://example.com/d"}}
+		itemErrors, err := repo.CreateBatch(links, false)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if itemErrors[0] != nil || links[0].SHORT_CODE != "fresh03" {
+			t.Errorf("Esperado o código regenerado fresh03, obtido %s (%v)", links[0].SHORT_CODE, itemErrors[0])
+		}
+
+		if !errors.Is(itemErrors[1], consts.ErrConflict) {
+			t.Errorf("Esperado ErrConflict para o alias ocupado, obtido %v", itemErrors[1])
+		}
+	})
+
+	t.Run("atomic batch", func(t *testing.T) {
+		repo := repositories.NewLinkRepository(db, &scriptedGenerator{codes: []string{"taken01", "fresh04"}})
+
+		links := []models.Links{{LONG_URL: "https://example.com/e"}}
+		if _, err := repo.CreateBatch(links, true); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if links[0].SHORT_CODE != "fresh04" {
+			t.Errorf("Esperado o código regenerado fresh04, obtido %s", links[0].SHORT_CODE)
+		}
+	})
+}
@@ -9,11 +9,14 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
@@ -22,20 +25,68 @@ import (
 	"linkfast/write-api/models"
 	"linkfast/write-api/repositories"
 	"linkfast/write-api/services"
+	"linkfast/write-api/utils/consts"
 )
 
-func setupApp() (*fiber.App, *gorm.DB) {
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+// SQLite has no schemas, so the database file is attached to itself as
+// link_fast_sc to resolve the "link_fast_sc.links" table name.
+func openTestDB() *gorm.DB {
+	dir, err := os.MkdirTemp("", "linkfast_test_")
+	if err != nil {
+		log.Fatalf("Falha ao criar o diretório do banco de teste: %v", err)
+	}
+	dsn := filepath.Join(dir, "links.db")
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalf("Falha ao conectar ao banco de dados de teste: %v", err)
 	}
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Falha ao obter a conexão SQL de teste: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.Exec("ATTACH DATABASE ? AS link_fast_sc", dsn).Error; err != nil {
+		log.Fatalf("Falha ao anexar o schema link_fast_sc: %v", err)
+	}
+
+	return db
+}
+
+func newTestRepository(db *gorm.DB) repositories.LinkRepository {
+	generator, err := repositories.NewCodeGenerator(repositories.CodeGeneratorConfig{
+		Strategy:   repositories.StrategyRandom,
+		Length:     7,
+		MaxRetries: 5,
+	})
+	if err != nil {
+		log.Fatalf("Falha ao criar o gerador de códigos de teste: %v", err)
+	}
+
+	return repositories.NewLinkRepository(db, generator)
+}
+
+type fakeClickCounts map[int64]int64
+
+func (f fakeClickCounts) GetClickCount(id int64) (int64, error) {
+	return f[id], nil
+}
+
+func setupApp() (*fiber.App, *gorm.DB) {
+	return setupAppWithClickCounts(nil)
+}
+
+func setupAppWithClickCounts(clickCounts repositories.ClickCountRepository) (*fiber.App, *gorm.DB) {
+	db := openTestDB()
+
 	if err := db.AutoMigrate(&models.Links{}); err != nil {
 		log.Fatalf("Falha ao executar a migração de teste: %v", err)
 	}
 
-	linkRepository := repositories.NewLinkRepository(db)
-	linkService := services.NewLinkService(linkRepository)
+	linkRepository := newTestRepository(db)
+	linkService := services.NewLinkService(linkRepository, clickCounts)
 	linkHandler := handlers.NewLinkHandler(linkService)
 
 	app := fiber.New()
@@ -46,7 +97,11 @@ func setupApp() (*fiber.App, *gorm.DB) {
 
 	v1 := app.Group("/v1")
 	v1.Post("/links", linkHandler.Create)
+	v1.Post("/links/batch", linkHandler.CreateBatch)
+	v1.Post("/links/import", linkHandler.Import)
+	v1.Get("/links/export", linkHandler.Export)
 	v1.Get("/links/:id", linkHandler.GetByID)
+	v1.Patch("/links/:id", linkHandler.Update)
 	v1.Delete("/links/:id", linkHandler.Delete)
 	v1.Get("/codes/:code", linkHandler.GetByShotCode)
 
@@ -57,10 +112,11 @@ func TestLinkHandler_Create_Integration(t *testing.T) {
 	app, db := setupApp()
 
 	tests := []struct {
-		description  string
-		payload      dtos.CreateLinkDto
-		expectedCode int
-		shouldExist  bool
+		description      string
+		payload          dtos.CreateLinkDto
+		expectedCode     int
+		shouldExist      bool
+		expectedRedirect int
 	}{
 		{
 			description: "Sucesso: Criação de link sem data de expiração (opcional)",
@@ -97,6 +153,54 @@ func TestLinkHandler_Create_Integration(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			shouldExist:  false,
 		},
+		{
+			description: "Sucesso: Criação de link com redirecionamento permanente",
+			payload: dtos.CreateLinkDto{
+				LONG_URL:     "https://www.google.com/test-url-4",
+				RedirectType: func() *int { r := 301; return &r }(),
+			},
+			expectedCode:     http.StatusCreated,
+			shouldExist:      true,
+			expectedRedirect: 301,
+		},
+		{
+			description: "Falha: Tipo de redirecionamento inválido",
+			payload: dtos.CreateLinkDto{
+				LONG_URL:     "https://www.google.com/test-url-5",
+				RedirectType: func() *int { r := 305; return &r }(),
+			},
+			expectedCode: http.StatusBadRequest,
+			shouldExist:  false,
+		},
+		{
+			description: "Sucesso: Criação de link com ativação agendada antes da expiração",
+			payload: dtos.CreateLinkDto{
+				LONG_URL:    "https://www.google.com/test-url-6",
+				ActivatesAt: func() *time.Time { t := time.Now().Add(1 * time.Hour); return &t }(),
+				ExpiresAt:   func() *time.Time { t := time.Now().Add(24 * time.Hour); return &t }(),
+			},
+			expectedCode: http.StatusCreated,
+			shouldExist:  true,
+		},
+		{
+			description: "Falha: Ativação agendada depois da expiração",
+			payload: dtos.CreateLinkDto{
+				LONG_URL:    "https://www.google.com/test-url-7",
+				ActivatesAt: func() *time.Time { t := time.Now().Add(48 * time.Hour); return &t }(),
+				ExpiresAt:   func() *time.Time { t := time.Now().Add(24 * time.Hour); return &t }(),
+			},
+			expectedCode: http.StatusBadRequest,
+			shouldExist:  false,
+		},
+		{
+			description: "Falha: Ativação agendada no passado",
+			payload: dtos.CreateLinkDto{
+				LONG_URL:    "https://www.google.com/test-url-8",
+				ActivatesAt: func() *time.Time { t := time.Now().Add(-1 * time.Hour); return &t }(),
+			},
+			expectedCode: http.StatusBadRequest,
+			shouldExist:  false,
+		},
 	}
 
 	for _, test := range tests {
@@ -143,14 +247,700 @@ func TestLinkHandler_Create_Integration(t *testing.T) {
 				if link.LONG_URL != test.payload.LONG_URL {
 					t.Errorf("Verificação de dados falhou: URL esperada %s, obtida %s.", test.payload.LONG_URL, link.LONG_URL)
 				}
+
+				expectedRedirect := test.expectedRedirect
+				if expectedRedirect == 0 {
+					expectedRedirect = consts.DefaultRedirectType
+				}
+
+				if link.RedirectType != expectedRedirect || response.Payload.RedirectType != expectedRedirect {
+					t.Errorf("Tipo de redirecionamento esperado %d, obtido %d (DB) e %d (resposta).", expectedRedirect, link.RedirectType, response.Payload.RedirectType)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkHandler_Create_Alias_Integration(t *testing.T) {
+	app, db := setupApp()
+	alias := func(value string) *string { return &value }
+
+	tests := []struct {
+		description  string
+		payload      dtos.CreateLinkDto
+		expectedCode int
+	}{
+		{
+			description: "Sucesso: Criação de link com alias personalizado",
+			payload: dtos.CreateLinkDto{
+				LONG_URL: "https://www.example.com/spring-sale",
+				Alias:    alias("spring-sale"),
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			description: "Falha: Alias já utilizado (409)",
+			payload: dtos.CreateLinkDto{
+				LONG_URL: "https://www.example.com/another-sale",
+				Alias:    alias("spring-sale"),
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			description: "Falha: Alias com caracteres inválidos",
+			payload: dtos.CreateLinkDto{
+				LONG_URL: "https://www.example.com/invalid-alias",
+				Alias:    alias("spring sale!"),
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description: "Falha: Alias muito curto",
+			payload: dtos.CreateLinkDto{
+				LONG_URL: "https://www.example.com/short-alias",
+				Alias:    alias("ab"),
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description: "Falha: Alias reservado",
+			payload: dtos.CreateLinkDto{
+				LONG_URL: "https://www.example.com/reserved-alias",
+				Alias:    alias("Health"),
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			payloadBytes, _ := json.Marshal(test.payload)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/links", bytes.NewReader(payloadBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 3000)
+			if err != nil {
+				t.Fatalf("Erro ao executar a requisição: %v", err)
+			}
+
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != test.expectedCode {
+				t.Errorf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s",
+					test.expectedCode, resp.StatusCode, bodyBytes)
+				return
+			}
+
+			if test.expectedCode == http.StatusCreated {
+				var link models.Links
+				if err := db.Where("short_code = ?", *test.payload.Alias).First(&link).Error; err != nil {
+					t.Errorf("Link com alias %s não foi encontrado no DB. Erro: %v", *test.payload.Alias, err)
+				}
+
+				if link.LONG_URL != test.payload.LONG_URL {
+					t.Errorf("URL esperada %s, obtida %s.", test.payload.LONG_URL, link.LONG_URL)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkHandler_Password_Integration(t *testing.T) {
+	app, db := setupApp()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/links", bytes.NewReader([]byte(`{"long_url": "https://www.example.com/private-doc", "password": "s3cret!"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusCreated, resp.StatusCode, bodyBytes)
+	}
+
+	if bytes.Contains(bodyBytes, []byte("s3cret!")) || bytes.Contains(bodyBytes, []byte("$2a$")) {
+		t.Errorf("A resposta não deveria expor a senha nem o hash: %s", bodyBytes)
+	}
+
+	var response struct {
+		Payload dtos.LinkDto `json:"payload"`
+	}
+	if err := json.Unmarshal(bodyBytes, &response); err != nil {
+		t.Fatalf("Falha ao decodificar a resposta JSON: %v", err)
+	}
+
+	if !response.Payload.PasswordProtected {
+		t.Errorf("O link deveria estar marcado como protegido por senha")
+	}
+
+	var link models.Links
+	if err := db.First(&link, response.Payload.ID).Error; err != nil {
+		t.Fatalf("Link não encontrado no DB: %v", err)
+	}
+
+	if link.PasswordHash == nil || bcrypt.CompareHashAndPassword([]byte(*link.PasswordHash), []byte("s3cret!")) != nil {
+		t.Fatalf("O hash armazenado não corresponde à senha")
+	}
+
+	req = httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/links/%d", link.ID), bytes.NewReader([]byte(`{"remove_password": true}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status code esperado: %d, obtido: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	db.First(&link, link.ID)
+	if link.PasswordProtected() {
+		t.Errorf("A senha deveria ter sido removida")
+	}
+}
+
+func TestLinkHandler_GeoTargets_Integration(t *testing.T) {
+	app, db := setupApp()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/links", bytes.NewReader([]byte(`{"long_url": "https://www.example.com/global", "geo_targets": {"usa": "https://www.example.com/us"}}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Código de país inválido deveria ser rejeitado, status obtido: %d", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/links", bytes.NewReader([]byte(`{"long_url": "https://www.example.com/global", "geo_targets": {"BR": "https://www.example.com/br", "US": "https://www.example.com/us"}}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+
+	var created struct {
+		Payload dtos.LinkDto `json:"payload"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Status code esperado: %d, obtido: %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	var link models.Links
+	if err := db.First(&link, created.Payload.ID).Error; err != nil {
+		t.Fatalf("Link não encontrado no DB: %v", err)
+	}
+
+	if link.GeoTargets["BR"] != "https://www.example.com/br" || len(link.GeoTargets) != 2 {
+		t.Fatalf("Regras de país não persistidas corretamente: %v", link.GeoTargets)
+	}
+
+	req = httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/links/%d", link.ID), bytes.NewReader([]byte(`{"geo_targets": {}}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status code esperado: %d, obtido: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	link = models.Links{}
+	db.First(&link, created.Payload.ID)
+	if link.GeoTargets != nil {
+		t.Errorf("As regras de país deveriam ter sido removidas, obtido: %v", link.GeoTargets)
+	}
+}
+
+func TestLinkHandler_DeviceTargets_Integration(t *testing.T) {
+	app, db := setupApp()
+
+	tests := []struct {
+		description  string
+		body         string
+		expectedCode int
+	}{
+		{
+			description:  "Sucesso: Regras por sistema e por classe de dispositivo",
+			body:         `{"long_url": "https://www.example.com/app", "device_targets": {"ios": "https://apps.apple.com/app/id123", "android": "https://play.google.com/store/apps/details?id=app", "desktop": "https://www.example.com/web"}}`,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			description:  "Falha: Plataforma desconhecida",
+			body:         `{"long_url": "https://www.example.com/app", "device_targets": {"symbian": "https://www.example.com/old"}}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/links", bytes.NewReader([]byte(test.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 3000)
+			if err != nil {
+				t.Fatalf("Erro ao executar a requisição: %v", err)
+			}
+
+			var created struct {
+				Payload dtos.LinkDto `json:"payload"`
+			}
+			json.NewDecoder(resp.Body).Decode(&created)
+			resp.Body.Close()
+
+			if resp.StatusCode != test.expectedCode {
+				t.Fatalf("Status code esperado: %d, obtido: %d", test.expectedCode, resp.StatusCode)
+			}
+
+			if test.expectedCode != http.StatusCreated {
+				return
+			}
+
+			var link models.Links
+			if err := db.First(&link, created.Payload.ID).Error; err != nil {
+				t.Fatalf("Link não encontrado no DB: %v", err)
+			}
+
+			if len(link.DeviceTargets) != 3 || link.DeviceTargets["ios"] != "https://apps.apple.com/app/id123" {
+				t.Errorf("Regras de dispositivo não persistidas corretamente: %v", link.DeviceTargets)
 			}
 		})
 	}
 }
 
+func TestLinkHandler_Variants_Integration(t *testing.T) {
+	app, db := setupApp()
+
+	tests := []struct {
+		description  string
+		body         string
+		expectedCode int
+	}{
+		{
+			description:  "Sucesso: Duas variantes com pesos",
+			body:         `{"long_url": "https://www.example.com/landing", "sticky_variants": true, "variants": [{"name": "a", "url": "https://www.example.com/landing-a", "weight": 70}, {"name": "b", "url": "https://www.example.com/landing-b", "weight": 30}]}`,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			description:  "Falha: Apenas uma variante",
+			body:         `{"long_url": "https://www.example.com/landing", "variants": [{"name": "a", "url": "https://www.example.com/landing-a", "weight": 1}]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "Falha: Nomes de variante repetidos",
+			body:         `{"long_url": "https://www.example.com/landing", "variants": [{"name": "a", "url": "https://www.example.com/landing-a", "weight": 1}, {"name": "a", "url": "https://www.example.com/landing-b", "weight": 1}]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "Falha: Peso zero",
+			body:         `{"long_url": "https://www.example.com/landing", "variants": [{"name": "a", "url": "https://www.example.com/landing-a", "weight": 0}, {"name": "b", "url": "https://www.example.com/landing-b", "weight": 1}]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/links", bytes.NewReader([]byte(test.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 3000)
+			if err != nil {
+				t.Fatalf("Erro ao executar a requisição: %v", err)
+			}
+
+			var created struct {
+				Payload dtos.LinkDto `json:"payload"`
+			}
+			json.NewDecoder(resp.Body).Decode(&created)
+			resp.Body.Close()
+
+			if resp.StatusCode != test.expectedCode {
+				t.Fatalf("Status code esperado: %d, obtido: %d", test.expectedCode, resp.StatusCode)
+			}
+
+			if test.expectedCode != http.StatusCreated {
+				return
+			}
+
+			var link models.Links
+			if err := db.First(&link, created.Payload.ID).Error; err != nil {
+				t.Fatalf("Link não encontrado no DB: %v", err)
+			}
+
+			if len(link.Variants) != 2 || link.Variants[1].Weight != 30 || !link.StickyVariants {
+				t.Errorf("Variantes não persistidas corretamente: %+v", link.Variants)
+			}
+
+			if len(created.Payload.Variants) != 2 || created.Payload.Variants[0].Name != "a" {
+				t.Errorf("Variantes ausentes na resposta: %+v", created.Payload.Variants)
+			}
+		})
+	}
+}
+
+func TestLinkHandler_CreateBatch_Integration(t *testing.T) {
+	app, db := setupApp()
+
+	if _, err := newTestRepository(db).Create(models.Links{SHORT_CODE: "taken-alias", LONG_URL: "https://www.example.com/taken"}); err != nil {
+		t.Fatalf("Setup falhou: não foi possível criar o link no DB: %v", err)
+	}
+
+	tests := []struct {
+		description      string
+		body             string
+		expectedCode     int
+		expectedCreated  int
+		expectedStatuses []string
+	}{
+		{
+			description:      "Sucesso: Lote atômico criado por inteiro",
+			body:             `{"links": [{"long_url": "https://www.example.com/crm-1"}, {"long_url": "https://www.example.com/crm-2", "alias": "crm-two"}, {"long_url": "https://www.example.com/crm-3"}]}`,
+			expectedCode:     http.StatusCreated,
+			expectedCreated:  3,
+			expectedStatuses: []string{dtos.BatchItemCreated, dtos.BatchItemCreated, dtos.BatchItemCreated},
+		},
+		{
+			description:      "Falha: Lote atômico com item inválido não cria nada",
+			body:             `{"mode": "atomic", "links": [{"long_url": "https://www.example.com/crm-4"}, {"long_url": "short"}]}`,
+			expectedCode:     http.StatusBadRequest,
+			expectedCreated:  0,
+			expectedStatuses: []string{dtos.BatchItemSkipped, dtos.BatchItemInvalid},
+		},
+		{
+			description:      "Falha: Lote atômico com alias em uso não cria nada",
+			body:             `{"links": [{"long_url": "https://www.example.com/crm-5"}, {"long_url": "https://www.example.com/crm-6", "alias": "taken-alias"}]}`,
+			expectedCode:     http.StatusConflict,
+			expectedCreated:  0,
+			expectedStatuses: []string{dtos.BatchItemSkipped, dtos.BatchItemConflict},
+		},
+		{
+			description:      "Parcial: Itens válidos criados e falhas reportadas por item",
+			body:             `{"mode": "partial", "links": [{"long_url": "https://www.example.com/crm-7", "alias": "dup-alias"}, {"long_url": "https://www.example.com/crm-8", "alias": "dup-alias"}, {"long_url": "short"}, {"long_url": "https://www.example.com/crm-9"}]}`,
+			expectedCode:     http.StatusMultiStatus,
+			expectedCreated:  2,
+			expectedStatuses: []string{dtos.BatchItemCreated, dtos.BatchItemConflict, dtos.BatchItemInvalid, dtos.BatchItemCreated},
+		},
+		{
+			description:  "Falha: Modo desconhecido",
+			body:         `{"mode": "best-effort", "links": [{"long_url": "https://www.example.com/crm-10"}]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var before int64
+			db.Model(&models.Links{}).Count(&before)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/links/batch", bytes.NewReader([]byte(test.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 5000)
+			if err != nil {
+				t.Fatalf("Erro ao executar a requisição: %v", err)
+			}
+
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != test.expectedCode {
+				t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", test.expectedCode, resp.StatusCode, bodyBytes)
+			}
+
+			var after int64
+			db.Model(&models.Links{}).Count(&after)
+			if int(after-before) != test.expectedCreated {
+				t.Errorf("Esperado %d links criados no DB, obtidos %d", test.expectedCreated, after-before)
+			}
+
+			if test.expectedStatuses == nil {
+				return
+			}
+
+			var response struct {
+				Payload dtos.BatchResultDto `json:"payload"`
+			}
+			if err := json.Unmarshal(bodyBytes, &response); err != nil {
+				t.Fatalf("Falha ao decodificar a resposta JSON: %v", err)
+			}
+
+			if response.Payload.Created != test.expectedCreated || len(response.Payload.Results) != len(test.expectedStatuses) {
+				t.Fatalf("Resultado inesperado: %+v", response.Payload)
+			}
+
+			for i, status := range test.expectedStatuses {
+				item := response.Payload.Results[i]
+				if item.Index != i || item.Status != status {
+					t.Errorf("Item %d: esperado %s, obtido %+v", i, status, item)
+				}
+
+				if status == dtos.BatchItemCreated && (item.Link == nil || item.Link.SHORT_CODE == "") {
+					t.Errorf("Item %d criado sem o link na resposta", i)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkHandler_ImportExport_Integration(t *testing.T) {
+	app, db := setupApp()
+
+	if _, err := newTestRepository(db).Create(models.Links{SHORT_CODE: "taken-import", LONG_URL: "https://www.example.com/taken"}); err != nil {
+		t.Fatalf("Setup falhou: não foi possível criar o link no DB: %v", err)
+	}
+
+	expiredAt := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	ownerID := int64(7)
+	if _, err := newTestRepository(db).Create(models.Links{SHORT_CODE: "imp-expired", LONG_URL: "https://www.example.com/expired", ExpiresAt: &expiredAt, OwnerID: &ownerID}); err != nil {
+		t.Fatalf("Setup falhou: não foi possível criar o link expirado no DB: %v", err)
+	}
+
+	send := func(method, path, contentType, body string) (int, []byte) {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		resp, err := app.Test(req, 5000)
+		if err != nil {
+			t.Fatalf("Erro ao executar a requisição: %v", err)
+		}
+		defer resp.Body.Close()
+
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, bodyBytes
+	}
+
+	importReport := func(body []byte) dtos.ImportReportDto {
+		var response struct {
+			Payload dtos.ImportReportDto `json:"payload"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			t.Fatalf("Falha ao decodificar a resposta JSON: %v", err)
+		}
+		return response.Payload
+	}
+
+	csvBody := "alias,long_url,expires_at,max_clicks,geo_targets\n" +
+		"imp-one,https://www.example.com/imp-1,,5,\"{\"\"BR\"\": \"\"https://www.example.com/imp-br\"\"}\"\n" +
+		",short,,,\n" +
+		",https://www.example.com/imp-3,tomorrow,,\n" +
+		"taken-import,https://www.example.com/imp-4,,,\n" +
+		",https://www.example.com/imp-5,,,\n"
+
+	code, body := send(http.MethodPost, "/v1/links/import?format=csv", "text/csv", csvBody)
+	if code != http.StatusMultiStatus {
+		t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusMultiStatus, code, body)
+	}
+
+	report := importReport(body)
+	if report.Total != 5 || report.Created != 2 || report.Failed != 3 || len(report.Errors) != 3 {
+		t.Fatalf("Relatório inesperado: %+v", report)
+	}
+
+	for i, line := range []int{3, 4, 5} {
+		if report.Errors[i].Line != line || len(report.Errors[i].Errors) == 0 {
+			t.Errorf("Erro %d: esperado na linha %d, obtido %+v", i, line, report.Errors[i])
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	jsonlBody := `{"long_url": "https://www.example.com/imp-6", "short_code": "imp-six", "redirect_type": 301}` + "\n\n{not-json\n" +
+		fmt.Sprintf(`{"long_url": "https://www.example.com/imp-7", "short_code": "imp-locked", "password_hash": %q}`, hash) + "\n" +
+		`{"long_url": "https://www.example.com/imp-8", "password_hash": "s3cret"}` + "\n"
+
+	code, body = send(http.MethodPost, "/v1/links/import", "application/x-ndjson", jsonlBody)
+	if code != http.StatusMultiStatus {
+		t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusMultiStatus, code, body)
+	}
+
+	if report := importReport(body); report.Created != 2 || report.Failed != 2 || report.Errors[0].Line != 3 || report.Errors[1].Line != 5 {
+		t.Fatalf("Relatório JSONL inesperado: %+v", report)
+	}
+
+	code, body = send(http.MethodGet, "/v1/links/export?format=jsonl", "", "")
+	if code != http.StatusOK {
+		t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusOK, code, body)
+	}
+
+	type exportedLink struct {
+		dtos.LinkDto
+		PasswordHash string `json:"password_hash"`
+	}
+
+	exported := map[string]exportedLink{}
+	for _, line := range bytes.Split(bytes.TrimSpace(body), []byte("\n")) {
+		var link exportedLink
+		if err := json.Unmarshal(line, &link); err != nil {
+			t.Fatalf("Linha exportada inválida %q: %v", line, err)
+		}
+		exported[link.SHORT_CODE] = link
+	}
+
+	if len(exported) != 6 {
+		t.Fatalf("Esperado 6 links exportados, obtidos %d", len(exported))
+	}
+
+	if link := exported["imp-expired"]; link.OwnerID == nil || *link.OwnerID != ownerID {
+		t.Errorf("Link imp-expired exportado sem o owner_id: %+v", link)
+	}
+
+	if link := exported["imp-locked"]; !link.PasswordProtected || link.PasswordHash != string(hash) {
+		t.Errorf("Link imp-locked exportado sem o hash da senha: %+v", link)
+	}
+
+	if link := exported["imp-one"]; link.MaxClicks == nil || *link.MaxClicks != 5 || link.GeoTargets["BR"] != "https://www.example.com/imp-br" {
+		t.Errorf("Link imp-one exportado incorretamente: %+v", link)
+	}
+
+	if link := exported["imp-six"]; link.RedirectType != 301 {
+		t.Errorf("Link imp-six exportado incorretamente: %+v", link)
+	}
+
+	code, body = send(http.MethodGet, "/v1/links/export", "", "")
+	if code != http.StatusOK {
+		t.Fatalf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s", http.StatusOK, code, body)
+	}
+
+	exportedCSV := string(body)
+
+	// Importar de volta o CSV exportado só gera conflitos, pois os short codes já existem.
+	code, body = send(http.MethodPost, "/v1/links/import", "text/csv", exportedCSV)
+	if report := importReport(body); code != http.StatusMultiStatus || report.Total != 6 || report.Created != 0 {
+		t.Fatalf("Reimportação inesperada (%d): %+v", code, report)
+	}
+
+	// Restaurar o CSV em um banco vazio mantém o link protegido pela mesma senha.
+	restoreApp, restoreDB := setupApp()
+	req := httptest.NewRequest(http.MethodPost, "/v1/links/import", bytes.NewReader([]byte(exportedCSV)))
+	req.Header.Set("Content-Type", "text/csv")
+
+	resp, err := restoreApp.Test(req, 5000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status code esperado: %d, obtido: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var restored models.Links
+	if err := restoreDB.Where("short_code = ?", "imp-locked").First(&restored).Error; err != nil {
+		t.Fatalf("Link imp-locked não foi restaurado: %v", err)
+	}
+
+	if restored.PasswordHash == nil || bcrypt.CompareHashAndPassword([]byte(*restored.PasswordHash), []byte("s3cret")) != nil {
+		t.Errorf("Link imp-locked restaurado sem a senha original: %v", restored.PasswordHash)
+	}
+
+	// O link expirado volta expirado e com o mesmo dono.
+	var expired models.Links
+	if err := restoreDB.Where("short_code = ?", "imp-expired").First(&expired).Error; err != nil {
+		t.Fatalf("Link imp-expired não foi restaurado: %v", err)
+	}
+
+	if expired.ExpiresAt == nil || !expired.ExpiresAt.Equal(expiredAt) || expired.OwnerID == nil || *expired.OwnerID != ownerID {
+		t.Errorf("Link imp-expired restaurado incorretamente: expires_at %v, owner_id %v", expired.ExpiresAt, expired.OwnerID)
+	}
+
+	if code, _ := send(http.MethodGet, "/v1/links/export?format=xml", "", ""); code != http.StatusBadRequest {
+		t.Errorf("Status code esperado: %d, obtido: %d", http.StatusBadRequest, code)
+	}
+}
+
+func TestLinkHandler_MaxClicks_Integration(t *testing.T) {
+	clickCounts := fakeClickCounts{}
+	app, _ := setupAppWithClickCounts(clickCounts)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/links", bytes.NewReader([]byte(`{"long_url": "https://www.example.com/giveaway", "max_clicks": 0}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("max_clicks 0 deveria ser rejeitado, status obtido: %d", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/links", bytes.NewReader([]byte(`{"long_url": "https://www.example.com/giveaway", "max_clicks": 3}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+
+	var created struct {
+		Payload dtos.LinkDto `json:"payload"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Status code esperado: %d, obtido: %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	if created.Payload.MaxClicks == nil || *created.Payload.MaxClicks != 3 {
+		t.Fatalf("max_clicks esperado: 3, obtido: %v", created.Payload.MaxClicks)
+	}
+
+	for _, tc := range []struct {
+		clicks    int64
+		remaining int
+	}{
+		{clicks: 0, remaining: 3},
+		{clicks: 2, remaining: 1},
+		{clicks: 5, remaining: 0},
+	} {
+		clickCounts[created.Payload.ID] = tc.clicks
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/links/%d", created.Payload.ID), nil), 3000)
+		if err != nil {
+			t.Fatalf("Erro ao executar a requisição: %v", err)
+		}
+
+		var found struct {
+			Payload dtos.LinkDto `json:"payload"`
+		}
+		json.NewDecoder(resp.Body).Decode(&found)
+		resp.Body.Close()
+
+		if found.Payload.RemainingClicks == nil || *found.Payload.RemainingClicks != tc.remaining {
+			t.Errorf("Com %d cliques, remaining_clicks esperado: %d, obtido: %v", tc.clicks, tc.remaining, found.Payload.RemainingClicks)
+		}
+	}
+}
+
 func TestLinkHandler_GetByID_Integration(t *testing.T) {
 	app, db := setupApp()
-	repo := repositories.NewLinkRepository(db)
+	repo := newTestRepository(db)
 
 	validLinkToFind := models.Links{
 		LONG_URL: "https://www.example.com/find-me",
@@ -229,7 +1019,7 @@ func TestLinkHandler_GetByID_Integration(t *testing.T) {
 
 func TestLinkHandler_GetByShotCode_Integration(t *testing.T) {
 	app, db := setupApp()
-	repo := repositories.NewLinkRepository(db)
+	repo := newTestRepository(db)
 
 	validLinkToFind := models.Links{
 		LONG_URL: "https://www.example.com/find-by-code",
@@ -300,9 +1090,180 @@ func TestLinkHandler_GetByShotCode_Integration(t *testing.T) {
 	}
 }
 
+func TestLinkHandler_Update_Integration(t *testing.T) {
+	app, db := setupApp()
+	repo := newTestRepository(db)
+
+	createdLink, err := repo.Create(models.Links{
+		LONG_URL: "https://www.example.com/typo-url",
+	})
+	if err != nil {
+		t.Fatalf("Setup falhou: não foi possível criar o link para atualização: %v", err)
+	}
+
+	future := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		description  string
+		id           string
+		body         string
+		expectedCode int
+		expectedURL  string
+	}{
+		{
+			description:  "Sucesso: Atualização da URL de destino",
+			id:           fmt.Sprintf("%d", createdLink.ID),
+			body:         `{"long_url": "https://www.example.com/fixed-url"}`,
+			expectedCode: http.StatusOK,
+			expectedURL:  "https://www.example.com/fixed-url",
+		},
+		{
+			description:  "Sucesso: Extensão da data de expiração mantém a URL",
+			id:           fmt.Sprintf("%d", createdLink.ID),
+			body:         fmt.Sprintf(`{"expires_at": "%s"}`, future.Format(time.RFC3339)),
+			expectedCode: http.StatusOK,
+			expectedURL:  "https://www.example.com/fixed-url",
+		},
+		{
+			description:  "Falha: Corpo sem campos para atualizar",
+			id:           fmt.Sprintf("%d", createdLink.ID),
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "Falha: URL inválida (Validação 'min' do DTO)",
+			id:           fmt.Sprintf("%d", createdLink.ID),
+			body:         `{"long_url": "http"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "Falha: Data de expiração passada",
+			id:           fmt.Sprintf("%d", createdLink.ID),
+			body:         `{"expires_at": "2000-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "Falha: expires_at junto com clear_expires_at",
+			id:           fmt.Sprintf("%d", createdLink.ID),
+			body:         fmt.Sprintf(`{"expires_at": "%s", "clear_expires_at": true}`, future.Format(time.RFC3339)),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "Falha: Data de ativação passada",
+			id:           fmt.Sprintf("%d", createdLink.ID),
+			body:         `{"activates_at": "2000-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "Falha: activates_at junto com clear_activates_at",
+			id:           fmt.Sprintf("%d", createdLink.ID),
+			body:         fmt.Sprintf(`{"activates_at": "%s", "clear_activates_at": true}`, future.Format(time.RFC3339)),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "Falha: ID não encontrado (404)",
+			id:           "999999",
+			body:         `{"long_url": "https://www.example.com/missing"}`,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			description:  "Falha: ID inválido (não numérico)",
+			id:           "abc",
+			body:         `{"long_url": "https://www.example.com/invalid"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/v1/links/"+test.id, bytes.NewReader([]byte(test.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 3000)
+			if err != nil {
+				t.Fatalf("Erro ao executar a requisição: %v", err)
+			}
+
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != test.expectedCode {
+				t.Errorf("Status code esperado: %d, obtido: %d. Corpo da resposta: %s",
+					test.expectedCode, resp.StatusCode, bodyBytes)
+				return
+			}
+
+			if test.expectedCode == http.StatusOK {
+				var link models.Links
+				if err := db.First(&link, createdLink.ID).Error; err != nil {
+					t.Fatalf("Link não encontrado no DB após atualização: %v", err)
+				}
+
+				if link.LONG_URL != test.expectedURL {
+					t.Errorf("URL esperada %s, obtida %s", test.expectedURL, link.LONG_URL)
+				}
+
+				if link.SHORT_CODE != createdLink.SHORT_CODE {
+					t.Errorf("O short code não deveria mudar: esperado %s, obtido %s", createdLink.SHORT_CODE, link.SHORT_CODE)
+				}
+			}
+		})
+	}
+
+	var link models.Links
+	db.First(&link, createdLink.ID)
+	if link.ExpiresAt == nil || !link.ExpiresAt.Equal(future) {
+		t.Errorf("Data de expiração esperada %v, obtida %v", future, link.ExpiresAt)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/links/%d", createdLink.ID), bytes.NewReader([]byte(`{"clear_expires_at": true}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, 3000)
+	if err != nil {
+		t.Fatalf("Erro ao executar a requisição: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status code esperado: %d, obtido: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var cleared models.Links
+	db.First(&cleared, createdLink.ID)
+	if cleared.ExpiresAt != nil {
+		t.Errorf("A data de expiração deveria ter sido removida, obtida %v", cleared.ExpiresAt)
+	}
+
+	activation := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	for _, body := range []string{
+		fmt.Sprintf(`{"activates_at": "%s"}`, activation.Format(time.RFC3339)),
+		`{"clear_activates_at": true}`,
+	} {
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/links/%d", createdLink.ID), bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req, 3000)
+		if err != nil {
+			t.Fatalf("Erro ao executar a requisição: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Status code esperado: %d, obtido: %d", http.StatusOK, resp.StatusCode)
+		}
+	}
+
+	var reactivated models.Links
+	db.First(&reactivated, createdLink.ID)
+	if reactivated.ActivatesAt != nil {
+		t.Errorf("A data de ativação deveria ter sido removida, obtida %v", reactivated.ActivatesAt)
+	}
+}
+
 func TestLinkHandler_Delete_Integration(t *testing.T) {
 	app, db := setupApp()
-	repo := repositories.NewLinkRepository(db)
+	repo := newTestRepository(db)
 
 	linkToDelete := models.Links{
 		LONG_URL: "https://www.example.com/to-be-deleted",
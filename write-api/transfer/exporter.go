@@ -0,0 +1,170 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/models"
+	"linkfast/write-api/services"
+	"linkfast/write-api/utils/consts"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+const exportPageSize = 1000
+
+var csvHeader = []string{
+	"id", "short_code", "long_url", "created_at", "expires_at", "activates_at", "redirect_type",
+	"max_clicks", "geo_targets", "device_targets", "variants", "sticky_variants",
+	"password_hash", "owner_id",
+}
+
+// exportRecord carries the password hash next to the LinkDto, so importing the file
+// back keeps protected links protected.
+type exportRecord struct {
+	dtos.LinkDto
+	PasswordHash *string `json:"password_hash,omitempty"`
+}
+
+// Exporter walks the links table by primary key so the export holds at most one page in
+// memory and is not affected by links created while it runs.
+type Exporter struct {
+	service services.LinkService
+}
+
+func NewExporter(service services.LinkService) *Exporter {
+	return &Exporter{service: service}
+}
+
+func (e *Exporter) Export(w io.Writer, format string) (int, error) {
+	var write func(link models.Links) error
+	var flush func() error
+
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return 0, err
+		}
+		write = func(link models.Links) error { return writeCSV(writer, link) }
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		write = func(link models.Links) error {
+			record := exportRecord{PasswordHash: link.PasswordHash}
+			if err := copier.Copy(&record.LinkDto, &link); err != nil {
+				return err
+			}
+			return encoder.Encode(record)
+		}
+		flush = func() error { return nil }
+	default:
+		return 0, consts.ErrUnknownFormat
+	}
+
+	total := 0
+	var afterID int64
+
+	for {
+		links, err := e.service.ListAfterID(afterID, exportPageSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, link := range links {
+			if err := write(link); err != nil {
+				return total, err
+			}
+		}
+
+		total += len(links)
+		if err := flush(); err != nil {
+			return total, err
+		}
+
+		if len(links) < exportPageSize {
+			return total, nil
+		}
+		afterID = links[len(links)-1].ID
+	}
+}
+
+func writeCSV(writer *csv.Writer, link models.Links) error {
+	geoTargets, err := jsonCell(link.GeoTargets, len(link.GeoTargets) == 0)
+	if err != nil {
+		return err
+	}
+
+	deviceTargets, err := jsonCell(link.DeviceTargets, len(link.DeviceTargets) == 0)
+	if err != nil {
+		return err
+	}
+
+	variants, err := jsonCell(link.Variants, len(link.Variants) == 0)
+	if err != nil {
+		return err
+	}
+
+	return writer.Write([]string{
+		strconv.FormatInt(link.ID, 10),
+		link.SHORT_CODE,
+		link.LONG_URL,
+		link.CreatedAt.UTC().Format(time.RFC3339),
+		timeCell(link.ExpiresAt),
+		timeCell(link.ActivatesAt),
+		strconv.Itoa(link.RedirectType),
+		intCell(link.MaxClicks),
+		geoTargets,
+		deviceTargets,
+		variants,
+		strconv.FormatBool(link.StickyVariants),
+		stringCell(link.PasswordHash),
+		idCell(link.OwnerID),
+	})
+}
+
+func timeCell(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+func stringCell(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func intCell(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func idCell(value *int64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatInt(*value, 10)
+}
+
+func jsonCell(value interface{}, empty bool) (string, error) {
+	if empty {
+		return "", nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
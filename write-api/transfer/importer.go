@@ -0,0 +1,117 @@
+package transfer
+
+import (
+	"errors"
+	"io"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/services"
+	"linkfast/write-api/utils/consts"
+)
+
+const (
+	DefaultChunkSize = 500
+	maxReportedLines = 1000
+)
+
+// Validator returns the validation messages for a single link, or none when it is valid.
+type Validator func(link dtos.CreateLinkDto) []string
+
+// Importer streams links from a Reader into the service in partial batches, so a bad line
+// never prevents the rest of the file from being imported.
+type Importer struct {
+	service   services.LinkService
+	validate  Validator
+	chunkSize int
+}
+
+func NewImporter(service services.LinkService, validate Validator, chunkSize int) *Importer {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &Importer{service: service, validate: validate, chunkSize: chunkSize}
+}
+
+// Import returns an error only when the input cannot be read any further; the report
+// holds what was imported up to that point. createdBy and owner are recorded on every
+// imported link, except that keepOwners lets a row's own owner_id win, so an admin can
+// restore an export without handing every link to themselves.
+func (i *Importer) Import(reader Reader, createdBy, owner *int64, keepOwners bool) (dtos.ImportReportDto, error) {
+	report := dtos.ImportReportDto{Errors: []dtos.ImportLineErrorDto{}}
+
+	chunk := make([]dtos.CreateLinkDto, 0, i.chunkSize)
+	lines := make([]int, 0, i.chunkSize)
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		items, err := i.service.CreateBatch(chunk, false)
+		if err != nil {
+			return err
+		}
+
+		for k, item := range items {
+			switch {
+			case item.Link != nil:
+				report.Created++
+			case errors.Is(item.Err, consts.ErrConflict):
+				fail(&report, lines[k], []string{"Alias already in use"})
+			case item.Err != nil:
+				fail(&report, lines[k], []string{item.Err.Error()})
+			}
+		}
+
+		chunk = chunk[:0]
+		lines = lines[:0]
+		return nil
+	}
+
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		report.Total++
+
+		if row.Err != nil {
+			fail(&report, row.Line, []string{row.Err.Error()})
+			continue
+		}
+
+		if messages := i.validate(row.Link); len(messages) > 0 {
+			fail(&report, row.Line, messages)
+			continue
+		}
+
+		row.Link.CreatedByKeyID = createdBy
+		row.Link.OwnerID = owner
+		if keepOwners && row.OwnerID != nil {
+			row.Link.OwnerID = row.OwnerID
+		}
+		chunk = append(chunk, row.Link)
+		lines = append(lines, row.Line)
+
+		if len(chunk) == i.chunkSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, flush()
+}
+
+func fail(report *dtos.ImportReportDto, line int, messages []string) {
+	report.Failed++
+
+	if len(report.Errors) == maxReportedLines {
+		report.ErrorsTruncated = true
+		return
+	}
+	report.Errors = append(report.Errors, dtos.ImportLineErrorDto{Line: line, Errors: messages})
+}
@@ -0,0 +1,280 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"linkfast/write-api/dtos"
+	"linkfast/write-api/utils/consts"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+const maxJSONLLineSize = 1 << 20
+
+// Row is one input record. Err is set when the record could not be parsed; it is reported
+// against Line and the import moves on to the next record. OwnerID is the owner_id the
+// record names, which the importer only trusts from admins.
+type Row struct {
+	Line    int
+	Link    dtos.CreateLinkDto
+	OwnerID *int64
+	Err     error
+}
+
+type Reader interface {
+	// Next returns io.EOF once the input is exhausted.
+	Next() (Row, error)
+}
+
+func NewReader(r io.Reader, format string) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+		return &jsonlReader{scanner: scanner}, nil
+	default:
+		return nil, consts.ErrUnknownFormat
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// newCSVReader reads the header first. "short_code" is accepted as an alias column so a
+// file produced by the export can be imported back as is.
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty csv", consts.ErrInvalidImport)
+		}
+		return nil, fmt.Errorf("%w: %v", consts.ErrInvalidImport, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+
+	if _, ok := columns["long_url"]; !ok {
+		return nil, fmt.Errorf("%w: csv header has no long_url column", consts.ErrInvalidImport)
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvReader) Next() (Row, error) {
+	record, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return Row{}, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Row{Line: parseErr.Line, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return Row{}, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	row := Row{Line: line}
+	row.Link, row.Err = c.parse(record)
+	if row.Err == nil {
+		row.OwnerID, row.Err = parseID(c.value(record, "owner_id"), "owner_id")
+	}
+	return row, nil
+}
+
+func (c *csvReader) value(record []string, column string) string {
+	i, ok := c.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func (c *csvReader) parse(record []string) (dtos.CreateLinkDto, error) {
+	link := dtos.CreateLinkDto{LONG_URL: c.value(record, "long_url")}
+
+	alias := c.value(record, "alias")
+	if alias == "" {
+		alias = c.value(record, "short_code")
+	}
+	if alias != "" {
+		link.Alias = &alias
+	}
+
+	var err error
+	if link.ExpiresAt, err = parseTime(c.value(record, "expires_at"), "expires_at"); err != nil {
+		return link, err
+	}
+
+	if link.ActivatesAt, err = parseTime(c.value(record, "activates_at"), "activates_at"); err != nil {
+		return link, err
+	}
+
+	if link.RedirectType, err = parseInt(c.value(record, "redirect_type"), "redirect_type"); err != nil {
+		return link, err
+	}
+
+	if link.MaxClicks, err = parseInt(c.value(record, "max_clicks"), "max_clicks"); err != nil {
+		return link, err
+	}
+
+	if err := parseJSON(c.value(record, "geo_targets"), "geo_targets", &link.GeoTargets); err != nil {
+		return link, err
+	}
+
+	if err := parseJSON(c.value(record, "device_targets"), "device_targets", &link.DeviceTargets); err != nil {
+		return link, err
+	}
+
+	if err := parseJSON(c.value(record, "variants"), "variants", &link.Variants); err != nil {
+		return link, err
+	}
+
+	if sticky := c.value(record, "sticky_variants"); sticky != "" {
+		if link.StickyVariants, err = strconv.ParseBool(sticky); err != nil {
+			return link, fmt.Errorf("sticky_variants: %q is not a boolean", sticky)
+		}
+	}
+
+	if link.PasswordHash, err = parsePasswordHash(c.value(record, "password_hash")); err != nil {
+		return link, err
+	}
+
+	return link, nil
+}
+
+// parsePasswordHash only accepts bcrypt hashes, the format the export writes, so a typo
+// cannot lock a link behind a password nobody knows.
+func parsePasswordHash(value string) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if _, err := bcrypt.Cost([]byte(value)); err != nil {
+		return nil, errors.New("password_hash: not a bcrypt hash")
+	}
+	return &value, nil
+}
+
+func parseTime(value, column string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q is not an RFC 3339 time", column, value)
+	}
+	return &parsed, nil
+}
+
+func parseInt(value, column string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q is not a number", column, value)
+	}
+	return &parsed, nil
+}
+
+func parseID(value, column string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed <= 0 {
+		return nil, fmt.Errorf("%s: %q is not an id", column, value)
+	}
+	return &parsed, nil
+}
+
+func parseJSON(value, column string, target interface{}) error {
+	if value == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(value), target); err != nil {
+		return fmt.Errorf("%s: invalid json: %v", column, err)
+	}
+	return nil
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// jsonlRecord also accepts the short_code, password_hash and owner_id of an exported link.
+type jsonlRecord struct {
+	dtos.CreateLinkDto
+	ShortCode    *string `json:"short_code"`
+	PasswordHash string  `json:"password_hash"`
+	OwnerID      *int64  `json:"owner_id"`
+}
+
+func (j *jsonlReader) Next() (Row, error) {
+	for j.scanner.Scan() {
+		j.line++
+
+		raw := bytes.TrimSpace(j.scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var record jsonlRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return Row{Line: j.line, Err: fmt.Errorf("invalid json: %v", err)}, nil
+		}
+
+		if record.Alias == nil && record.ShortCode != nil && *record.ShortCode != "" {
+			record.Alias = record.ShortCode
+		}
+
+		hash, err := parsePasswordHash(record.PasswordHash)
+		if err != nil {
+			return Row{Line: j.line, Err: err}, nil
+		}
+		record.CreateLinkDto.PasswordHash = hash
+
+		return Row{Line: j.line, Link: record.CreateLinkDto, OwnerID: record.OwnerID}, nil
+	}
+
+	if err := j.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return Row{}, fmt.Errorf("%w: line %d is longer than %d bytes", consts.ErrInvalidImport, j.line+1, maxJSONLLineSize)
+		}
+		return Row{}, err
+	}
+
+	return Row{}, io.EOF
+}
@@ -1,6 +1,9 @@
 package consts
 
-import "errors"
+import (
+	"errors"
+	"linkfast/shared/auth"
+)
 
 var (
 	ErrRecordNotFound = errors.New("data not found")
@@ -8,4 +11,66 @@ var (
 	ErrInternalDB     = errors.New("internal database error.")
 	ErrInternal       = errors.New("internal error in server.")
 	ErrFieldNull      = errors.New("field is null")
+	ErrInvalidWindow  = errors.New("activates_at must be before expires_at")
+
+	ErrShortCodeExhausted = errors.New("could not generate a unique short code")
+	ErrBatchRejected      = errors.New("batch rejected, no link was created")
+	ErrUnknownFormat      = errors.New("unknown format, expected csv or jsonl")
+	ErrInvalidImport      = errors.New("invalid import file")
+
+	ErrInvalidApiKey         = errors.New("invalid or revoked api key")
+	ErrWeakBootstrap         = errors.New("bootstrap api key must have at least 32 characters")
+	ErrWeakBootstrapPassword = errors.New("bootstrap admin password must have at least 12 characters")
+
+	ErrInvalidCredentials = errors.New("invalid email or password")
 )
+
+const DefaultRedirectType = 307
+
+// DeviceTargetKeys are the platforms a device_targets rule can match, an operating system or a device class.
+var DeviceTargetKeys = []string{
+	"ios",
+	"android",
+	"windows",
+	"macos",
+	"linux",
+	"mobile",
+	"tablet",
+	"desktop",
+}
+
+const (
+	ScopeLinksRead   = "links:read"
+	ScopeLinksWrite  = "links:write"
+	ScopeLinksDelete = "links:delete"
+	ScopeKeysAdmin   = "keys:admin"
+)
+
+var ApiKeyScopes = []string{
+	ScopeLinksRead,
+	ScopeLinksWrite,
+	ScopeLinksDelete,
+	ScopeKeysAdmin,
+}
+
+// UserScopes are held by every user; admins hold all of ApiKeyScopes.
+var UserScopes = []string{
+	ScopeLinksRead,
+	ScopeLinksWrite,
+	ScopeLinksDelete,
+}
+
+const (
+	RoleUser  = auth.RoleUser
+	RoleAdmin = auth.RoleAdmin
+)
+
+var ReservedAliases = []string{
+	"api",
+	"health",
+	"admin",
+	"links",
+	"code",
+	"static",
+	"metrics",
+}